@@ -0,0 +1,125 @@
+-- 文章表
+CREATE TABLE IF NOT EXISTS `article` (
+    `id`               BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    `title`            VARCHAR(255) NOT NULL DEFAULT '',
+    `slug`             VARCHAR(191) NOT NULL COMMENT '由标题生成的唯一访问标识',
+    `picture`          VARCHAR(512) NOT NULL DEFAULT '',
+    `picture_variants` JSON NULL COMMENT '图片缩略图及宽度版本',
+    `summary`          VARCHAR(1024) NOT NULL DEFAULT '',
+    `content_format`   VARCHAR(16) NOT NULL DEFAULT 'plain' COMMENT '内容格式：plain、markdown、html',
+    `category_id`      BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '所属分类ID，0表示未分类',
+    `author_id`        BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '作者的用户ID，0表示没有作者',
+    `external_id`      VARCHAR(191) NULL COMMENT '导入文章在原系统中的ID，用于去重',
+    `status`           VARCHAR(16) NOT NULL DEFAULT 'published' COMMENT '文章状态：draft、scheduled、published、archived',
+    `published_at`     DATETIME NULL COMMENT '发布时间，定时发布时为计划发布时间',
+    `created_at`       DATETIME NULL,
+    `updated_at`       DATETIME NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_slug` (`slug`),
+    UNIQUE KEY `uk_external_id` (`external_id`),
+    KEY `idx_category_id` (`category_id`),
+    KEY `idx_author_id` (`author_id`),
+    KEY `idx_status_published_at` (`status`, `published_at`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
+
+-- 文章内容表
+CREATE TABLE IF NOT EXISTS `article_content` (
+    `article_id`   BIGINT UNSIGNED NOT NULL,
+    `content`      LONGTEXT        NOT NULL COMMENT '文章原始内容',
+    `content_html` LONGTEXT        NOT NULL COMMENT '渲染并过滤后的HTML内容',
+    PRIMARY KEY (`article_id`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
+
+-- 标签表
+CREATE TABLE IF NOT EXISTS `tag` (
+    `id`         BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    `name`       VARCHAR(64) NOT NULL,
+    `created_at` DATETIME NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_name` (`name`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
+
+-- 文章标签关联表
+CREATE TABLE IF NOT EXISTS `article_tag` (
+    `article_id` BIGINT UNSIGNED NOT NULL,
+    `tag_id`     BIGINT UNSIGNED NOT NULL,
+    PRIMARY KEY (`article_id`, `tag_id`),
+    KEY `idx_tag_id` (`tag_id`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
+
+-- 分类表
+CREATE TABLE IF NOT EXISTS `category` (
+    `id`         BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    `parent_id`  BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '父分类ID，0表示顶级分类',
+    `name`       VARCHAR(64) NOT NULL,
+    `created_at` DATETIME NULL,
+    `updated_at` DATETIME NULL,
+    PRIMARY KEY (`id`),
+    KEY `idx_parent_id` (`parent_id`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
+
+-- 文章版本表
+CREATE TABLE IF NOT EXISTS `article_revision` (
+    `id`             BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    `article_id`     BIGINT UNSIGNED NOT NULL,
+    `revision`       INT NOT NULL COMMENT '文章内的版本号，从1开始递增',
+    `title`          VARCHAR(255) NOT NULL DEFAULT '',
+    `picture`        VARCHAR(512) NOT NULL DEFAULT '',
+    `content_format` VARCHAR(16) NOT NULL DEFAULT 'plain',
+    `content`        LONGTEXT NOT NULL,
+    `created_at`     DATETIME NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_article_revision` (`article_id`, `revision`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
+
+-- 文章历史访问标识表，用于旧链接跳转
+CREATE TABLE IF NOT EXISTS `article_slug_redirect` (
+    `slug`       VARCHAR(191) NOT NULL,
+    `article_id` BIGINT UNSIGNED NOT NULL,
+    `created_at` DATETIME NULL,
+    PRIMARY KEY (`slug`),
+    KEY `idx_article_id` (`article_id`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
+
+-- 检索同义词表，每行一组互为同义词的检索词
+CREATE TABLE IF NOT EXISTS `search_synonym` (
+    `id`         BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    `terms`      JSON NOT NULL,
+    `created_at` DATETIME NULL,
+    `updated_at` DATETIME NULL,
+    PRIMARY KEY (`id`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
+
+-- 检索停用词表
+CREATE TABLE IF NOT EXISTS `search_stopword` (
+    `id`         BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    `word`       VARCHAR(64) NOT NULL,
+    `created_at` DATETIME NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_word` (`word`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
+
+-- 用户表，首次以JWT访问写接口时按subject创建
+CREATE TABLE IF NOT EXISTS `user` (
+    `id`         BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    `subject`    VARCHAR(191) NOT NULL COMMENT 'JWT的sub',
+    `name`       VARCHAR(64) NOT NULL DEFAULT '',
+    `created_at` DATETIME NULL,
+    `updated_at` DATETIME NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_subject` (`subject`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
+
+-- API Key表，只保存密钥的SHA-256哈希
+CREATE TABLE IF NOT EXISTS `api_key` (
+    `id`         BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    `name`       VARCHAR(64) NOT NULL,
+    `prefix`     VARCHAR(16) NOT NULL COMMENT '密钥前几位，便于识别',
+    `key_hash`   CHAR(64) NOT NULL,
+    `scopes`     JSON NOT NULL COMMENT '权限范围：articles:read、articles:write、admin',
+    `created_by` BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '创建者的用户ID',
+    `created_at` DATETIME NULL,
+    `revoked_at` DATETIME NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_key_hash` (`key_hash`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"demo/src/common/auth"
+	"demo/src/dtos"
+	"demo/src/repositories"
+	"demo/src/services"
+	"flag"
+	"github.com/gin-gonic/gin/binding"
+	"log"
+	"net/url"
+	"os"
+)
+
+// runCommand 执行命令行子命令
+func runCommand(name string, args []string) {
+	switch name {
+	case "export":
+		runExport(args)
+	case "import":
+		runImport(args)
+	case "migrate-db":
+		runMigrateDB(args)
+	case "migrate-index":
+		runMigrateIndex(args)
+	default:
+		log.Fatalf("Unknown command %q, available commands: export, import, migrate-db, migrate-index", name)
+	}
+}
+
+// commandContext 命令行以本地管理员身份执行，权限仍按策略判断
+func commandContext() context.Context {
+	return auth.WithPrincipal(context.Background(), &auth.Principal{
+		Subject: auth.CLISubject,
+		Name:    "cli",
+		Roles:   []string{auth.RoleAdmin},
+	})
+}
+
+// runExport 导出文章到文件，过滤条件与文章列表接口的查询参数一致，如：
+// demo export -format csv -o articles.csv -query "tags=go&created_from=2024-01-01T00:00:00Z"
+func runExport(args []string) {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	format := flags.String("format", services.ExportFormatNDJSON, "export format: ndjson, csv or json")
+	output := flags.String("o", "", "output file (default articles.<format>)")
+	query := flags.String("query", "", "list filters as a query string, e.g. tags=go&sort=updated_at")
+	_ = flags.Parse(args)
+
+	values, err := url.ParseQuery(*query)
+	if err != nil {
+		log.Fatalf("Invalid -query: %v", err)
+	}
+	var req dtos.ArticleExportRequest
+	if err := binding.MapFormWithTag(&req, values, "form"); err != nil {
+		log.Fatalf("Invalid -query: %v", err)
+	}
+	req.Format = *format
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		log.Fatalf("Invalid export options: %v", err)
+	}
+	if *output == "" {
+		*output = "articles." + req.Format
+	}
+
+	db := repositories.InitDB()
+	esClient := repositories.InitElasticsearch()
+	exportService := services.NewExportService(db, esClient, auth.InitPolicy())
+
+	f, err := os.Create(*output)
+	if err != nil {
+		log.Fatalf("Failed to create %s: %v", *output, err)
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	if err := exportService.ExportArticles(commandContext(), w, &req); err != nil {
+		log.Fatalf("Failed to export articles: %v", err)
+	}
+	if err := w.Flush(); err != nil {
+		log.Fatalf("Failed to write %s: %v", *output, err)
+	}
+	log.Printf("Articles exported to %s", *output)
+}
+
+// runImport 从导出文件或Markdown目录导入文章，如：
+// demo import -format markdown ./posts
+func runImport(args []string) {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	format := flags.String("format", services.ImportFormatNDJSON, "import format: ndjson, csv or markdown (a directory of .md files)")
+	_ = flags.Parse(args)
+	if flags.NArg() != 1 {
+		log.Fatalf("Usage: demo import -format ndjson|csv|markdown <file or directory>")
+	}
+
+	db := repositories.InitDB()
+	esClient := repositories.InitElasticsearch()
+	rdb := repositories.InitRedis()
+	storage := repositories.InitStorage()
+	policy := auth.InitPolicy()
+
+	uploadService := services.NewUploadService(storage, policy)
+	categoryService := services.NewCategoryService(db, esClient, policy)
+	userService := services.NewUserService(db, esClient)
+	articleService := services.NewArticleService(db, esClient, rdb, uploadService, categoryService, userService, policy)
+	importService := services.NewImportService(db, articleService)
+
+	ctx := commandContext()
+	if err := articleService.CheckArticleIndex(ctx); err != nil {
+		log.Fatalf("Article index check failed: %v", err)
+	}
+
+	summary, err := importService.ImportArticles(ctx, *format, flags.Arg(0))
+	for _, failure := range summary.Failures {
+		log.Printf("Failed to import %s: %s", failure.Source, failure.Error)
+	}
+	log.Printf("Import finished: %d created, %d skipped, %d failed", summary.Created, summary.Skipped, summary.Failed)
+	if err != nil {
+		log.Fatalf("Import aborted: %v", err)
+	}
+}
+
+// runMigrateDB 升级旧版本部署的数据库表结构，先执行init.sql创建新增的表，如：
+// mysql demo < init.sql && demo migrate-db
+func runMigrateDB(args []string) {
+	flags := flag.NewFlagSet("migrate-db", flag.ExitOnError)
+	_ = flags.Parse(args)
+
+	db := repositories.InitDB()
+	esClient := repositories.InitElasticsearch()
+	rdb := repositories.InitRedis()
+	storage := repositories.InitStorage()
+	policy := auth.InitPolicy()
+
+	uploadService := services.NewUploadService(storage, policy)
+	categoryService := services.NewCategoryService(db, esClient, policy)
+	userService := services.NewUserService(db, esClient)
+	articleService := services.NewArticleService(db, esClient, rdb, uploadService, categoryService, userService, policy)
+
+	if err := articleService.MigrateSchema(commandContext()); err != nil {
+		log.Fatalf("Failed to migrate database: %v", err)
+	}
+	log.Printf("Database schema is up to date")
+}
+
+// runMigrateIndex 文章索引的映射或分析器配置变化后，新建版本化的索引并从MySQL重建，完成后切换别名，如：
+// demo migrate-index -force
+func runMigrateIndex(args []string) {
+	flags := flag.NewFlagSet("migrate-index", flag.ExitOnError)
+	force := flags.Bool("force", false, "rebuild even if the index is already up to date")
+	_ = flags.Parse(args)
+
+	db := repositories.InitDB()
+	esClient := repositories.InitElasticsearch()
+	rdb := repositories.InitRedis()
+	storage := repositories.InitStorage()
+	policy := auth.InitPolicy()
+
+	uploadService := services.NewUploadService(storage, policy)
+	categoryService := services.NewCategoryService(db, esClient, policy)
+	userService := services.NewUserService(db, esClient)
+	articleService := services.NewArticleService(db, esClient, rdb, uploadService, categoryService, userService, policy)
+	searchTermService := services.NewSearchTermService(db, esClient, policy)
+
+	ctx := commandContext()
+	// 新索引的检索分析器创建时从同义词文件加载
+	if err := searchTermService.WriteTermsFile(ctx); err != nil {
+		log.Fatalf("Failed to write the synonyms file: %v", err)
+	}
+
+	index, previous, err := articleService.MigrateArticleIndex(ctx, *force)
+	if err != nil {
+		log.Fatalf("Failed to migrate article index: %v", err)
+	}
+	switch previous {
+	case index:
+		log.Printf("Article index %s is up to date", index)
+	case "", repositories.ArticleIndexAlias:
+		log.Printf("Article index migrated to %s", index)
+	default:
+		log.Printf("Article index migrated to %s, the previous index %s is kept and can be deleted once no longer needed", index, previous)
+	}
+}
@@ -0,0 +1,210 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"demo/src/common/env"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"log"
+	"math/big"
+	"os"
+	"strings"
+)
+
+// ErrInvalidToken 令牌无效、过期或签名校验失败
+var ErrInvalidToken = errors.New("invalid token")
+
+// minHS256SecretLength HS256密钥的最小长度（字节），与SHA-256的输出长度一致，过短的密钥可被离线暴力破解
+const minHS256SecretLength = 32
+
+// Verifier 校验HS256或RS256签名的JWT
+type Verifier struct {
+	secret     []byte                    // HS256密钥
+	publicKey  *rsa.PublicKey            // RS256公钥，令牌没有kid或JWKS中找不到kid时使用
+	jwks       map[string]*rsa.PublicKey // 本地JWKS文件中的RS256公钥，key为kid
+	issuer     string
+	audience   string
+	rolesClaim string
+}
+
+// InitVerifier 从环境变量创建JWT校验器，至少需要配置一种密钥
+func InitVerifier() *Verifier {
+	v := &Verifier{
+		secret:     []byte(env.String("JWT_HS256_SECRET", "")),
+		issuer:     env.String("JWT_ISSUER", ""),
+		audience:   env.String("JWT_AUDIENCE", ""),
+		rolesClaim: env.String("JWT_ROLES_CLAIM", "roles"),
+	}
+
+	if len(v.secret) > 0 && len(v.secret) < minHS256SecretLength {
+		log.Fatalf("JWT_HS256_SECRET must be at least %d bytes, generate one with `openssl rand -base64 32`", minHS256SecretLength)
+	}
+
+	if path := env.String("JWT_RS256_PUBLIC_KEY_FILE", ""); path != "" {
+		key, err := loadPublicKey(path)
+		if err != nil {
+			log.Fatalf("Invalid JWT_RS256_PUBLIC_KEY_FILE: %v", err)
+		}
+		v.publicKey = key
+	}
+
+	if path := env.String("JWT_JWKS_FILE", ""); path != "" {
+		keys, err := loadJWKS(path)
+		if err != nil {
+			log.Fatalf("Invalid JWT_JWKS_FILE: %v", err)
+		}
+		v.jwks = keys
+	}
+
+	if len(v.secret) == 0 && v.publicKey == nil && len(v.jwks) == 0 {
+		log.Fatalf("No JWT key configured, set JWT_HS256_SECRET, JWT_RS256_PUBLIC_KEY_FILE or JWT_JWKS_FILE")
+	}
+	return v
+}
+
+// Verify 校验令牌并返回调用方
+func (v *Verifier) Verify(tokenString string) (*Principal, error) {
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg(), jwt.SigningMethodRS256.Alg()}),
+		jwt.WithExpirationRequired(),
+	}
+	if v.issuer != "" {
+		options = append(options, jwt.WithIssuer(v.issuer))
+	}
+	if v.audience != "" {
+		options = append(options, jwt.WithAudience(v.audience))
+	}
+
+	claims := jwt.MapClaims{}
+	if _, err := jwt.ParseWithClaims(tokenString, claims, v.key, options...); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidToken, err)
+	}
+
+	subject, _ := claims.GetSubject()
+	if subject == "" {
+		return nil, fmt.Errorf("%w: missing subject", ErrInvalidToken)
+	}
+	if IsReservedSubject(subject) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidToken, ErrReservedSubject)
+	}
+	name, _ := claims["name"].(string)
+
+	return &Principal{
+		Subject: subject,
+		Name:    name,
+		Roles:   parseRoles(claims[v.rolesClaim]),
+	}, nil
+}
+
+// key 根据令牌的签名算法和kid选择校验密钥
+func (v *Verifier) key(token *jwt.Token) (interface{}, error) {
+	switch token.Method.Alg() {
+	case jwt.SigningMethodHS256.Alg():
+		if len(v.secret) == 0 {
+			return nil, errors.New("HS256 is not enabled")
+		}
+		return v.secret, nil
+	case jwt.SigningMethodRS256.Alg():
+		if kid, ok := token.Header["kid"].(string); ok && kid != "" {
+			if key, ok := v.jwks[kid]; ok {
+				return key, nil
+			}
+		}
+		if v.publicKey == nil {
+			return nil, errors.New("no RS256 key for the token")
+		}
+		return v.publicKey, nil
+	default:
+		return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+	}
+}
+
+// parseRoles 解析角色声明，支持字符串数组或空格分隔的字符串
+func parseRoles(claim interface{}) []string {
+	var roles []string
+	switch value := claim.(type) {
+	case []interface{}:
+		for _, role := range value {
+			if s, ok := role.(string); ok && s != "" {
+				roles = append(roles, s)
+			}
+		}
+	case string:
+		roles = strings.Fields(value)
+	}
+	return roles
+}
+
+// loadPublicKey 读取PEM格式的RSA公钥
+func loadPublicKey(path string) (*rsa.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return jwt.ParseRSAPublicKeyFromPEM(data)
+}
+
+// loadJWKS 读取本地JWKS文件中的RSA公钥，忽略其他类型的密钥
+func loadJWKS(path string) (map[string]*rsa.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var jwks struct {
+		Keys []struct {
+			Kid string   `json:"kid"`
+			Kty string   `json:"kty"`
+			N   string   `json:"n"`
+			E   string   `json:"e"`
+			X5c []string `json:"x5c"`
+		} `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &jwks); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]*rsa.PublicKey)
+	for _, k := range jwks.Keys {
+		if k.Kty != "RSA" || k.Kid == "" {
+			continue
+		}
+
+		// 优先使用n和e，没有时从证书链中取公钥
+		if k.N != "" && k.E != "" {
+			n, err := base64.RawURLEncoding.DecodeString(k.N)
+			if err != nil {
+				return nil, fmt.Errorf("key %s: invalid n: %w", k.Kid, err)
+			}
+			e, err := base64.RawURLEncoding.DecodeString(k.E)
+			if err != nil {
+				return nil, fmt.Errorf("key %s: invalid e: %w", k.Kid, err)
+			}
+			keys[k.Kid] = &rsa.PublicKey{
+				N: new(big.Int).SetBytes(n),
+				E: int(new(big.Int).SetBytes(e).Int64()),
+			}
+			continue
+		}
+		if len(k.X5c) > 0 {
+			der, err := base64.StdEncoding.DecodeString(k.X5c[0])
+			if err != nil {
+				return nil, fmt.Errorf("key %s: invalid x5c: %w", k.Kid, err)
+			}
+			cert, err := x509.ParseCertificate(der)
+			if err != nil {
+				return nil, fmt.Errorf("key %s: %w", k.Kid, err)
+			}
+			key, ok := cert.PublicKey.(*rsa.PublicKey)
+			if !ok {
+				return nil, fmt.Errorf("key %s: certificate is not RSA", k.Kid)
+			}
+			keys[k.Kid] = key
+		}
+	}
+	return keys, nil
+}
@@ -0,0 +1,151 @@
+package auth
+
+import (
+	"demo/src/common/env"
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"log"
+	"os"
+)
+
+// 权限
+const (
+	PermCreate         = "create"          // 新增文章
+	PermEditOwn        = "edit_own"        // 修改自己的文章
+	PermEditAny        = "edit_any"        // 修改任何人的文章
+	PermPublish        = "publish"         // 发布文章，包括定时发布
+	PermDelete         = "delete"          // 删除文章
+	PermManageTaxonomy = "manage_taxonomy" // 管理分类、同义词和停用词
+	PermManageAPIKeys  = "manage_api_keys" // 创建和吊销API Key
+	PermExport         = "export"          // 导出文章
+)
+
+// 内置角色
+const (
+	RoleReader = "reader" // 读者
+	RoleAuthor = "author" // 作者
+	RoleEditor = "editor" // 编辑
+	RoleAdmin  = "admin"  // 管理员
+)
+
+// API Key的权限范围，与角色分开配置和判断，JWT中同名的角色不会获得scope的权限
+const (
+	ScopeArticlesRead  = "articles:read"  // 只读
+	ScopeArticlesWrite = "articles:write" // 新增文章和修改自己的文章
+	ScopeAdmin         = "admin"          // 与admin角色相同
+)
+
+// knownPermissions 全部权限，用于校验策略文件
+var knownPermissions = map[string]bool{
+	PermCreate: true, PermEditOwn: true, PermEditAny: true,
+	PermPublish: true, PermDelete: true, PermManageTaxonomy: true, PermManageAPIKeys: true, PermExport: true,
+}
+
+// defaultRolePermissions 未配置策略文件时使用的角色权限
+var defaultRolePermissions = map[string][]string{
+	RoleReader: {},
+	RoleAuthor: {PermCreate, PermEditOwn},
+	RoleEditor: {PermCreate, PermEditOwn, PermEditAny, PermPublish, PermManageTaxonomy, PermExport},
+	RoleAdmin:  {PermCreate, PermEditOwn, PermEditAny, PermPublish, PermDelete, PermManageTaxonomy, PermManageAPIKeys, PermExport},
+}
+
+// defaultScopePermissions 未配置策略文件或策略文件没有scopes时使用的API Key权限范围
+var defaultScopePermissions = map[string][]string{
+	ScopeArticlesRead:  {PermExport},
+	ScopeArticlesWrite: {PermCreate, PermEditOwn},
+	ScopeAdmin:         defaultRolePermissions[RoleAdmin],
+}
+
+// Policy 角色和API Key权限范围的权限策略
+type Policy struct {
+	roles  map[string]map[string]bool
+	scopes map[string]map[string]bool
+}
+
+// NewPolicy 根据角色和权限范围的权限列表创建策略
+func NewPolicy(rolePermissions, scopePermissions map[string][]string) (*Policy, error) {
+	roles, err := permissionSets("role", rolePermissions)
+	if err != nil {
+		return nil, err
+	}
+	scopes, err := permissionSets("scope", scopePermissions)
+	if err != nil {
+		return nil, err
+	}
+	return &Policy{roles: roles, scopes: scopes}, nil
+}
+
+// permissionSets 将权限列表转换为集合并校验权限名称
+func permissionSets(kind string, permissionLists map[string][]string) (map[string]map[string]bool, error) {
+	sets := make(map[string]map[string]bool)
+	for name, permissions := range permissionLists {
+		sets[name] = make(map[string]bool)
+		for _, permission := range permissions {
+			if !knownPermissions[permission] {
+				return nil, fmt.Errorf("%s %s: unknown permission %q", kind, name, permission)
+			}
+			sets[name][permission] = true
+		}
+	}
+	return sets, nil
+}
+
+// InitPolicy 加载AUTH_POLICY_FILE指定的策略文件，未配置时使用默认策略
+func InitPolicy() *Policy {
+	rolePermissions, scopePermissions := defaultRolePermissions, defaultScopePermissions
+	if path := env.String("AUTH_POLICY_FILE", ""); path != "" {
+		var err error
+		if rolePermissions, scopePermissions, err = loadPolicyFile(path); err != nil {
+			log.Fatalf("Invalid AUTH_POLICY_FILE: %v", err)
+		}
+	}
+
+	policy, err := NewPolicy(rolePermissions, scopePermissions)
+	if err != nil {
+		log.Fatalf("Invalid auth policy: %v", err)
+	}
+	return policy
+}
+
+// loadPolicyFile 读取YAML格式的策略文件，格式为roles: {角色: [权限, ...]}, scopes: {权限范围: [权限, ...]}，
+// 没有scopes时使用默认的权限范围
+func loadPolicyFile(path string) (map[string][]string, map[string][]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var file struct {
+		Roles  map[string][]string `yaml:"roles"`
+		Scopes map[string][]string `yaml:"scopes"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, nil, err
+	}
+	if len(file.Roles) == 0 {
+		return nil, nil, errors.New("no roles defined")
+	}
+	if file.Scopes == nil {
+		file.Scopes = defaultScopePermissions
+	}
+	return file.Roles, file.Scopes, nil
+}
+
+// Allowed 判断调用方是否拥有指定权限：JWT调用方按角色判断，API Key调用方按权限范围判断，匿名调用方没有任何权限
+func (p *Policy) Allowed(principal *Principal, permission string) bool {
+	if principal == nil {
+		return false
+	}
+	for _, role := range principal.Roles {
+		if p.roles[role][permission] {
+			return true
+		}
+	}
+	for _, scope := range principal.Scopes {
+		if p.scopes[scope][permission] {
+			return true
+		}
+	}
+	return false
+}
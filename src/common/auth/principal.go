@@ -0,0 +1,49 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ErrReservedSubject subject使用了API Key或命令行调用方保留的形式
+var ErrReservedSubject = errors.New("subject is reserved for api keys and the command line")
+
+// CLISubject 命令行调用方的subject
+const CLISubject = "cli"
+
+// apiKeySubjectPrefix API Key调用方的subject前缀，后接API Key的ID
+const apiKeySubjectPrefix = "apikey:"
+
+// Principal 已认证的调用方
+type Principal struct {
+	Subject string   // JWT的sub
+	Name    string   // JWT的name，可能为空
+	Roles   []string // JWT调用方的角色列表
+	Scopes  []string // API Key调用方的权限范围，与角色分开判断
+}
+
+// APIKeySubject API Key调用方的subject
+func APIKeySubject(apiKeyID uint64) string {
+	return apiKeySubjectPrefix + strconv.FormatUint(apiKeyID, 10)
+}
+
+// IsReservedSubject 判断subject是否为API Key或命令行调用方保留的形式；JWT和导入文件中的subject
+// 与调用方共用user表的subject，不能使用保留的形式，否则会对应到API Key或命令行的用户并获得其文章的权限
+func IsReservedSubject(subject string) bool {
+	return subject == CLISubject || strings.HasPrefix(subject, apiKeySubjectPrefix)
+}
+
+type principalKey struct{}
+
+// WithPrincipal 将调用方保存到context中
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// FromContext 从context中获取调用方，匿名请求返回nil
+func FromContext(ctx context.Context) *Principal {
+	principal, _ := ctx.Value(principalKey{}).(*Principal)
+	return principal
+}
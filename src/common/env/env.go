@@ -0,0 +1,47 @@
+package env
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+// String 获取字符串配置，未设置时返回默认值
+func String(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// Int64 获取整数配置，未设置时返回默认值
+func Int64(key string, defaultValue int64) int64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s: %v", key, err)
+	}
+	return n
+}
+
+// Int 获取整数配置，未设置时返回默认值
+func Int(key string, defaultValue int) int {
+	return int(Int64(key, int64(defaultValue)))
+}
+
+// Duration 获取时长配置（如30s、5m），未设置时返回默认值
+func Duration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		log.Fatalf("Invalid %s: %v", key, err)
+	}
+	return d
+}
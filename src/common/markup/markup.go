@@ -0,0 +1,83 @@
+package markup
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"html"
+	"strings"
+)
+
+// 文章内容格式
+const (
+	FormatPlain    = "plain"
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+)
+
+var (
+	// markdown 渲染器，启用GFM扩展（表格、删除线、自动链接、任务列表）
+	markdown = goldmark.New(goldmark.WithExtensions(extension.GFM))
+
+	// policy HTML白名单策略，过滤script、事件属性、javascript:链接等危险内容
+	policy = bluemonday.UGCPolicy()
+
+	// stripPolicy 去除全部HTML标签，用于生成摘要
+	stripPolicy = bluemonday.StrictPolicy().AddSpaceWhenStrippingTag(true)
+)
+
+// NormalizeFormat 规范化内容格式，空值视为纯文本
+func NormalizeFormat(format string) string {
+	if format == "" {
+		return FormatPlain
+	}
+	return format
+}
+
+// Sanitize 过滤HTML中的危险标签和属性
+func Sanitize(source string) string {
+	return policy.Sanitize(source)
+}
+
+// Render 将指定格式的文章内容渲染为安全的HTML
+func Render(format, source string) (string, error) {
+	switch NormalizeFormat(format) {
+	case FormatPlain:
+		return renderPlain(source), nil
+	case FormatMarkdown:
+		var buf bytes.Buffer
+		if err := markdown.Convert([]byte(source), &buf); err != nil {
+			return "", err
+		}
+		return Sanitize(buf.String()), nil
+	case FormatHTML:
+		return Sanitize(source), nil
+	default:
+		return "", fmt.Errorf("unsupported content format: %s", format)
+	}
+}
+
+// renderPlain 纯文本转义后按空行分段，段内换行转为<br>
+func renderPlain(source string) string {
+	source = strings.ReplaceAll(source, "\r\n", "\n")
+
+	var buf strings.Builder
+	for _, paragraph := range strings.Split(source, "\n\n") {
+		paragraph = strings.TrimSpace(paragraph)
+		if paragraph == "" {
+			continue
+		}
+		buf.WriteString("<p>")
+		buf.WriteString(strings.ReplaceAll(html.EscapeString(paragraph), "\n", "<br>"))
+		buf.WriteString("</p>\n")
+	}
+	return buf.String()
+}
+
+// PlainText 去除HTML标签，返回纯文本内容
+func PlainText(source string) string {
+	text := html.UnescapeString(stripPolicy.Sanitize(source))
+	return strings.Join(strings.Fields(text), " ")
+}
@@ -2,10 +2,38 @@ package redis_keys
 
 import "fmt"
 
-const Lock = "lock"
+const (
+	Lock        = "lock"
+	Leader      = "leader"
+	Related     = "related"
+	RateLimit   = "ratelimit"
+	Idempotency = "idempotency"
+
+	APIKeyLastUsed = "api_key:last_used" // API Key最后使用时间的hash，field为API Key ID
+)
 
 // GetArticleIdLockedKey 获取文章锁的键名
 func GetArticleIdLockedKey(articleId uint64) (lockedKey string) {
 	lockedKey = fmt.Sprintf("%s:%d", Lock, articleId)
 	return
 }
+
+// GetLeaderKey 获取后台任务选主的键名
+func GetLeaderKey(name string) string {
+	return fmt.Sprintf("%s:%s", Leader, name)
+}
+
+// GetRelatedArticlesKey 获取相关文章缓存的键名
+func GetRelatedArticlesKey(articleID uint64) string {
+	return fmt.Sprintf("%s:%d", Related, articleID)
+}
+
+// GetRateLimitKey 获取限流计数的键名
+func GetRateLimitKey(key string) string {
+	return fmt.Sprintf("%s:%s", RateLimit, key)
+}
+
+// GetIdempotencyKey 获取幂等请求记录的键名
+func GetIdempotencyKey(key string) string {
+	return fmt.Sprintf("%s:%s", Idempotency, key)
+}
@@ -0,0 +1,148 @@
+package textdiff
+
+import (
+	"github.com/sergi/go-diff/diffmatchpatch"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// 差异片段类型
+const (
+	OpEqual  = "equal"
+	OpInsert = "insert"
+	OpDelete = "delete"
+)
+
+// 差异比较粒度
+const (
+	ModeLine = "line"
+	ModeWord = "word"
+)
+
+// diffTimeout 单次比较的最长耗时，超时后返回较粗粒度的结果
+const diffTimeout = 2 * time.Second
+
+// Op 差异片段
+type Op struct {
+	Type string `json:"type"` // equal、insert、delete
+	Text string `json:"text"`
+}
+
+// Diff 按行或按词比较两段文本的差异
+func Diff(from, to, mode string) []Op {
+	split := splitLines
+	if mode == ModeWord {
+		split = splitWords
+	}
+
+	// 将每个行/词映射为一个字符后做字符级比较，比较完成后再还原
+	tokens := []string{}
+	tokenIndex := make(map[string]int)
+	encode := func(text string) []rune {
+		parts := split(text)
+		runes := make([]rune, len(parts))
+		for i, part := range parts {
+			index, ok := tokenIndex[part]
+			if !ok {
+				index = len(tokens)
+				tokens = append(tokens, part)
+				tokenIndex[part] = index
+			}
+			runes[i] = indexToRune(index)
+		}
+		return runes
+	}
+	fromRunes, toRunes := encode(from), encode(to)
+
+	dmp := diffmatchpatch.New()
+	dmp.DiffTimeout = diffTimeout
+	diffs := dmp.DiffMainRunes(fromRunes, toRunes, false)
+
+	ops := make([]Op, 0, len(diffs))
+	for _, d := range diffs {
+		var text strings.Builder
+		for _, r := range d.Text {
+			text.WriteString(tokens[runeToIndex(r)])
+		}
+
+		op := Op{Type: OpEqual, Text: text.String()}
+		switch d.Type {
+		case diffmatchpatch.DiffInsert:
+			op.Type = OpInsert
+		case diffmatchpatch.DiffDelete:
+			op.Type = OpDelete
+		}
+		ops = append(ops, op)
+	}
+	return ops
+}
+
+// splitLines 按行拆分，保留换行符
+func splitLines(text string) []string {
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1] // 以换行结尾时去掉末尾的空行
+	}
+	return lines
+}
+
+// splitWords 按词拆分：连续的字母数字为一个词，中日韩文字每个字为一个词，连续空白为一个片段，其他符号单独成片段
+func splitWords(text string) []string {
+	var parts []string
+	start := 0
+	for start < len(text) {
+		r, size := utf8.DecodeRuneInString(text[start:])
+		end := start + size
+
+		switch {
+		case unicode.IsSpace(r):
+			end = scan(text, end, unicode.IsSpace)
+		case isWordRune(r):
+			end = scan(text, end, isWordRune)
+		}
+
+		parts = append(parts, text[start:end])
+		start = end
+	}
+	return parts
+}
+
+// isWordRune 判断是否为可连续组成单词的字符（不包括中日韩文字）
+func isWordRune(r rune) bool {
+	return (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_') && !isCJK(r)
+}
+
+// isCJK 判断是否为中日韩文字
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
+
+// scan 从offset开始向后扫描满足条件的字符，返回结束位置
+func scan(text string, offset int, match func(rune) bool) int {
+	for offset < len(text) {
+		r, size := utf8.DecodeRuneInString(text[offset:])
+		if !match(r) {
+			break
+		}
+		offset += size
+	}
+	return offset
+}
+
+// indexToRune 将片段序号编码为字符，跳过UTF-16代理区间以保证是合法字符
+func indexToRune(index int) rune {
+	if index >= 0xD800 {
+		return rune(index + 0x800)
+	}
+	return rune(index)
+}
+
+// runeToIndex indexToRune的逆运算
+func runeToIndex(r rune) int {
+	if r >= 0xE000 {
+		return int(r) - 0x800
+	}
+	return int(r)
+}
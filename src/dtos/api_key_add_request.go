@@ -0,0 +1,7 @@
+package dtos
+
+// APIKeyAddRequest 接收创建API Key请求的JSON数据结构体
+type APIKeyAddRequest struct {
+	Name   string   `json:"name" binding:"required,max=64"`
+	Scopes []string `json:"scopes" binding:"required,min=1,dive,oneof=articles:read articles:write admin"`
+}
@@ -0,0 +1,14 @@
+package dtos
+
+import "demo/src/models"
+
+type APIKeyAddResultData struct {
+	models.APIKey
+	Key string `json:"key"` // 完整密钥，只在创建时返回一次
+}
+
+// APIKeyAddResponse 响应创建API Key请求的JSON数据结构体
+type APIKeyAddResponse struct {
+	Data    APIKeyAddResultData `json:"data"`
+	Message string              `json:"message"`
+}
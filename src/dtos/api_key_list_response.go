@@ -0,0 +1,13 @@
+package dtos
+
+import "demo/src/models"
+
+type APIKeyListData struct {
+	List []models.APIKey `json:"list"`
+}
+
+// APIKeyListResponse 响应查询API Key列表请求的JSON数据结构体
+type APIKeyListResponse struct {
+	Data    APIKeyListData `json:"data"`
+	Message string         `json:"message"`
+}
@@ -0,0 +1,11 @@
+package dtos
+
+type APIKeyRevokeResultData struct {
+	APIKeyID uint64 `json:"api_key_id"`
+}
+
+// APIKeyRevokeResponse 响应吊销API Key请求的JSON数据结构体
+type APIKeyRevokeResponse struct {
+	Data    APIKeyRevokeResultData `json:"data"`
+	Message string                 `json:"message"`
+}
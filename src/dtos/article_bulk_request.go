@@ -0,0 +1,16 @@
+package dtos
+
+import "time"
+
+// ArticleBulkItem 批量写入请求中的一篇文章，article_id为0时新增，否则更新对应文章
+type ArticleBulkItem struct {
+	ArticleID uint64 `json:"article_id"`
+	ArticleUpdateRequest
+	// 以下字段只用于命令行导入新增的文章，不从请求中读取
+	ExternalID  string     `json:"-"` // 原系统中的ID
+	CreatedAt   *time.Time `json:"-"` // 保留原始创建时间
+	UpdatedAt   *time.Time `json:"-"` // 保留原始更新时间
+	PublishedAt *time.Time `json:"-"` // 保留已发布文章的原始发布时间
+	AuthorID    uint64     `json:"-"` // 原文章作者对应的用户，为0时作者为当前用户
+	AuthorName  string     `json:"-"`
+}
@@ -0,0 +1,22 @@
+package dtos
+
+// ArticleBulkResult 批量写入中单篇文章的结果
+type ArticleBulkResult struct {
+	Index     int    `json:"index"`                // 在请求中的位置，从0开始
+	ArticleID uint64 `json:"article_id,omitempty"` // 写入数据库成功时返回文章ID
+	Action    string `json:"action"`               // created或updated
+	Status    int    `json:"status"`               // 与单篇接口一致的HTTP状态码
+	Error     string `json:"error,omitempty"`
+}
+
+type ArticleBulkData struct {
+	Results   []ArticleBulkResult `json:"results"`
+	Succeeded int                 `json:"succeeded"`
+	Failed    int                 `json:"failed"`
+}
+
+// ArticleBulkResponse 响应批量写入文章请求的JSON数据结构体
+type ArticleBulkResponse struct {
+	Data    ArticleBulkData `json:"data"`
+	Message string          `json:"message"`
+}
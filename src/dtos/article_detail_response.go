@@ -0,0 +1,15 @@
+package dtos
+
+import "demo/src/models"
+
+type ArticleDetailData struct {
+	models.Article
+	Content     string `json:"content"`      // 文章原始内容
+	ContentHTML string `json:"content_html"` // 渲染后的HTML内容
+}
+
+// ArticleDetailResponse 响应文章详情请求的JSON数据结构体
+type ArticleDetailResponse struct {
+	Data    ArticleDetailData `json:"data"`
+	Message string            `json:"message"`
+}
@@ -0,0 +1,9 @@
+package dtos
+
+// ArticleExportRequest 接收导出文章请求的参数，过滤条件与文章列表一致
+type ArticleExportRequest struct {
+	Format string `form:"format" binding:"omitempty,oneof=ndjson csv json"` // 导出格式，默认ndjson
+	Sort   string `form:"sort" binding:"omitempty,oneof=id created_at updated_at title"`
+	Order  string `form:"order" binding:"omitempty,oneof=asc desc"`
+	ArticleFilter
+}
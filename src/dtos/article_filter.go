@@ -0,0 +1,19 @@
+package dtos
+
+import "time"
+
+// ArticleFilter 文章列表和导出共用的过滤条件
+type ArticleFilter struct {
+	Tags       []string `form:"tags"`                                        // 标签过滤，支持逗号分隔或重复参数
+	TagsMode   string   `form:"tags_mode" binding:"omitempty,oneof=any all"` // any匹配任一标签（默认），all匹配全部标签
+	CategoryID uint64   `form:"category_id"`                                 // 分类过滤，包含子分类下的文章
+	AuthorID   uint64   `form:"author_id"`                                   // 作者过滤
+	// 时间范围过滤，RFC3339格式，包含边界
+	CreatedFrom time.Time `form:"created_from"`
+	CreatedTo   time.Time `form:"created_to"`
+	UpdatedFrom time.Time `form:"updated_from"`
+	UpdatedTo   time.Time `form:"updated_to"`
+	HasPicture  *bool     `form:"has_picture"`                   // 是否有封面图，不传则不过滤
+	Q           string    `form:"q" binding:"omitempty,max=100"` // 关键词，检索标题、摘要和正文
+	Status      []string  `form:"status"`                        // 文章状态过滤，支持逗号分隔，只有能修改文章的调用方可用，默认只返回已发布的文章
+}
@@ -2,8 +2,12 @@ package dtos
 
 // ArticleListRequest 用于接收查询文章列表请求的JSON数据结构体
 type ArticleListRequest struct {
-	Page     int    `form:"page" binding:"required,min=1"`
+	Page     int    `form:"page" binding:"required_without=Cursor,min=0"` // 页码，使用游标分页时忽略
 	PageSize int    `form:"page_size" binding:"required,min=5,max=100"`
-	Sort     string `form:"sort" binding:"omitempty,oneof=id created_at"`
+	Sort     string `form:"sort" binding:"omitempty,oneof=id created_at updated_at title"`
 	Order    string `form:"order" binding:"omitempty,oneof=asc desc"`
+	Cursor   string `form:"cursor"` // 分页游标，取自上次响应的next_cursor或prev_cursor，适用于深度翻页
+	ArticleFilter
+	Fields []string `form:"fields"` // 只返回指定字段，支持逗号分隔，如fields=id,title
+	Facets []string `form:"facets"` // 分面统计，支持tags、category、month、has_picture，逗号分隔
 }
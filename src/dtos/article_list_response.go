@@ -1,17 +1,30 @@
 package dtos
 
-import "demo/src/models"
-
 type ArticleListPageData struct {
-	Total     int64 `json:"total"`
-	Page      int   `json:"page"`
-	PageSize  int   `json:"page_size"`
-	TotalPage int   `json:"total_page"`
+	Total      int64  `json:"total"`
+	Page       int    `json:"page"` // 游标分页时为0
+	PageSize   int    `json:"page_size"`
+	TotalPage  int    `json:"total_page"`
+	NextCursor string `json:"next_cursor,omitempty"` // 下一页游标，没有更多数据时为空
+	PrevCursor string `json:"prev_cursor,omitempty"` // 上一页游标，已是第一页时为空
+}
+
+type FacetBucket struct {
+	Key   string `json:"key"`   // 标签名、分类ID、月份（yyyy-MM）或true/false
+	Count int64  `json:"count"` // 符合当前过滤条件的文章数量
+}
+
+type ArticleListFacets struct {
+	Tags       []FacetBucket `json:"tags,omitempty"`
+	Category   []FacetBucket `json:"category,omitempty"`
+	Month      []FacetBucket `json:"month,omitempty"`
+	HasPicture []FacetBucket `json:"has_picture,omitempty"`
 }
 
 type ArticleListData struct {
 	PageData ArticleListPageData `json:"page_data"`
-	List     []models.Article    `json:"list"`
+	List     interface{}         `json:"list"`             // []models.Article，指定fields时为只包含所选字段的对象列表
+	Facets   *ArticleListFacets  `json:"facets,omitempty"` // 请求facets时返回
 }
 
 // ArticleListResponse 响应查询文章列表请求的JSON数据结构体
@@ -0,0 +1,8 @@
+package dtos
+
+import "time"
+
+// ArticlePublishRequest 接收发布文章请求的JSON数据结构体
+type ArticlePublishRequest struct {
+	PublishAt *time.Time `json:"publish_at"` // 定时发布时间，为空或早于当前时间时立即发布
+}
@@ -0,0 +1,6 @@
+package dtos
+
+// ArticleRelatedRequest 用于接收查询相关文章请求的数据结构体
+type ArticleRelatedRequest struct {
+	Size int `form:"size" binding:"omitempty,min=1,max=20"` // 返回数量，默认5
+}
@@ -0,0 +1,22 @@
+package dtos
+
+import "time"
+
+type RelatedArticle struct {
+	ID          uint64     `json:"id"`
+	Title       string     `json:"title"`
+	Slug        string     `json:"slug"`
+	Picture     string     `json:"picture"`
+	Summary     string     `json:"summary"`
+	PublishedAt *time.Time `json:"published_at"`
+}
+
+type ArticleRelatedData struct {
+	List []RelatedArticle `json:"list"`
+}
+
+// ArticleRelatedResponse 响应查询相关文章请求的JSON数据结构体
+type ArticleRelatedResponse struct {
+	Data    ArticleRelatedData `json:"data"`
+	Message string             `json:"message"`
+}
@@ -0,0 +1,8 @@
+package dtos
+
+// ArticleRevisionDiffRequest 用于接收比较文章版本请求的数据结构体
+type ArticleRevisionDiffRequest struct {
+	From int    `form:"from" binding:"required,min=1"`            // 旧版本号
+	To   int    `form:"to" binding:"required,min=1"`              // 新版本号
+	Mode string `form:"mode" binding:"omitempty,oneof=line word"` // 比较粒度：line按行（默认），word按词
+}
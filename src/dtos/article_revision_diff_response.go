@@ -0,0 +1,18 @@
+package dtos
+
+import "demo/src/common/textdiff"
+
+type ArticleRevisionDiffData struct {
+	From    int           `json:"from"`
+	To      int           `json:"to"`
+	Mode    string        `json:"mode"`
+	Title   []textdiff.Op `json:"title"`   // 标题差异
+	Picture []textdiff.Op `json:"picture"` // 图片差异
+	Content []textdiff.Op `json:"content"` // 内容差异
+}
+
+// ArticleRevisionDiffResponse 响应比较文章版本请求的JSON数据结构体
+type ArticleRevisionDiffResponse struct {
+	Data    ArticleRevisionDiffData `json:"data"`
+	Message string                  `json:"message"`
+}
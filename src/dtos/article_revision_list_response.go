@@ -0,0 +1,13 @@
+package dtos
+
+import "demo/src/models"
+
+type ArticleRevisionListData struct {
+	List []models.ArticleRevision `json:"list"` // 版本列表，不包含内容，按版本号倒序
+}
+
+// ArticleRevisionListResponse 响应查询文章版本列表请求的JSON数据结构体
+type ArticleRevisionListResponse struct {
+	Data    ArticleRevisionListData `json:"data"`
+	Message string                  `json:"message"`
+}
@@ -0,0 +1,9 @@
+package dtos
+
+import "demo/src/models"
+
+// ArticleRevisionResponse 响应查询文章版本详情请求的JSON数据结构体
+type ArticleRevisionResponse struct {
+	Data    models.ArticleRevision `json:"data"`
+	Message string                 `json:"message"`
+}
@@ -0,0 +1,7 @@
+package dtos
+
+// ArticleSuggestRequest 用于接收文章标题联想请求的数据结构体
+type ArticleSuggestRequest struct {
+	Prefix string `form:"prefix" binding:"required,max=100"`     // 用户已输入的标题前缀
+	Size   int    `form:"size" binding:"omitempty,min=1,max=20"` // 返回数量，默认10
+}
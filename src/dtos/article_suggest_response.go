@@ -0,0 +1,17 @@
+package dtos
+
+type ArticleSuggestion struct {
+	ID    uint64 `json:"id"`
+	Title string `json:"title"`
+	Slug  string `json:"slug"`
+}
+
+type ArticleSuggestData struct {
+	List []ArticleSuggestion `json:"list"`
+}
+
+// ArticleSuggestResponse 响应文章标题联想请求的JSON数据结构体
+type ArticleSuggestResponse struct {
+	Data    ArticleSuggestData `json:"data"`
+	Message string             `json:"message"`
+}
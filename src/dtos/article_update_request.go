@@ -1,8 +1,16 @@
 package dtos
 
+import "time"
+
 // ArticleUpdateRequest 接收文章更新请求的JSON数据结构体
 type ArticleUpdateRequest struct {
-	Title   string `json:"title" binding:"required"` // 文章标题不能为空值
-	Picture string `json:"picture"`
-	Content string `json:"content"`
+	Title         string     `json:"title" binding:"required"`         // 文章标题不能为空值
+	Slug          string     `json:"slug" binding:"omitempty,max=150"` // 自定义访问标识，为空时由标题生成
+	Picture       string     `json:"picture"`
+	Content       string     `json:"content"`
+	ContentFormat string     `json:"content_format" binding:"omitempty,oneof=plain markdown html"`        // 内容格式，默认plain
+	Tags          []string   `json:"tags" binding:"omitempty,max=10,dive,required,max=32"`                // 文章标签，最多10个
+	CategoryID    uint64     `json:"category_id"`                                                         // 所属分类ID，0表示未分类
+	Status        string     `json:"status" binding:"omitempty,oneof=draft scheduled published archived"` // 文章状态，为空时保持不变
+	PublishAt     *time.Time `json:"publish_at" binding:"required_if=Status scheduled"`                   // 定时发布时间，status为scheduled时必填
 }
@@ -0,0 +1,7 @@
+package dtos
+
+// CategoryAddRequest 接收新增分类请求的JSON数据结构体
+type CategoryAddRequest struct {
+	Name     string `json:"name" binding:"required,max=64"`
+	ParentID uint64 `json:"parent_id"` // 父分类ID，0表示顶级分类
+}
@@ -0,0 +1,11 @@
+package dtos
+
+type CategoryAddResultData struct {
+	CategoryID uint64 `json:"category_id"`
+}
+
+// CategoryAddResponse 响应新增分类请求的JSON数据结构体
+type CategoryAddResponse struct {
+	Data    CategoryAddResultData `json:"data"`
+	Message string                `json:"message"`
+}
@@ -0,0 +1,14 @@
+package dtos
+
+import "demo/src/models"
+
+type CategoryDetailData struct {
+	models.Category
+	Path []uint64 `json:"path"` // 从顶级分类到当前分类的ID路径
+}
+
+// CategoryDetailResponse 响应分类详情请求的JSON数据结构体
+type CategoryDetailResponse struct {
+	Data    CategoryDetailData `json:"data"`
+	Message string             `json:"message"`
+}
@@ -0,0 +1,13 @@
+package dtos
+
+import "demo/src/models"
+
+type CategoryListData struct {
+	List []*models.Category `json:"list"` // 顶级分类，子分类在children中
+}
+
+// CategoryListResponse 响应查询分类树请求的JSON数据结构体
+type CategoryListResponse struct {
+	Data    CategoryListData `json:"data"`
+	Message string           `json:"message"`
+}
@@ -0,0 +1,11 @@
+package dtos
+
+type CategoryUpdateResultData struct {
+	CategoryID uint64 `json:"category_id"`
+}
+
+// CategoryUpdateResponse 响应分类更新和删除请求的JSON数据结构体
+type CategoryUpdateResponse struct {
+	Data    CategoryUpdateResultData `json:"data"`
+	Message string                   `json:"message"`
+}
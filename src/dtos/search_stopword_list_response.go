@@ -0,0 +1,13 @@
+package dtos
+
+import "demo/src/models"
+
+type SearchStopwordListData struct {
+	List []models.SearchStopword `json:"list"`
+}
+
+// SearchStopwordListResponse 响应查询停用词列表请求的JSON数据结构体
+type SearchStopwordListResponse struct {
+	Data    SearchStopwordListData `json:"data"`
+	Message string                 `json:"message"`
+}
@@ -0,0 +1,6 @@
+package dtos
+
+// SearchStopwordRequest 接收新增停用词请求的JSON数据结构体
+type SearchStopwordRequest struct {
+	Word string `json:"word" binding:"required,max=64"`
+}
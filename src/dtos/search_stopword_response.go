@@ -0,0 +1,11 @@
+package dtos
+
+type SearchStopwordResultData struct {
+	StopwordID uint64 `json:"stopword_id"`
+}
+
+// SearchStopwordResponse 响应停用词新增和删除请求的JSON数据结构体
+type SearchStopwordResponse struct {
+	Data    SearchStopwordResultData `json:"data"`
+	Message string                   `json:"message"`
+}
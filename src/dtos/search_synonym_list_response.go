@@ -0,0 +1,13 @@
+package dtos
+
+import "demo/src/models"
+
+type SearchSynonymListData struct {
+	List []models.SearchSynonym `json:"list"`
+}
+
+// SearchSynonymListResponse 响应查询同义词组列表请求的JSON数据结构体
+type SearchSynonymListResponse struct {
+	Data    SearchSynonymListData `json:"data"`
+	Message string                `json:"message"`
+}
@@ -0,0 +1,6 @@
+package dtos
+
+// SearchSynonymRequest 接收新增和更新同义词组请求的JSON数据结构体
+type SearchSynonymRequest struct {
+	Terms []string `json:"terms" binding:"required,min=2,max=20,dive,required,max=50"` // 互为同义词的检索词
+}
@@ -0,0 +1,11 @@
+package dtos
+
+type SearchSynonymResultData struct {
+	SynonymID uint64 `json:"synonym_id"`
+}
+
+// SearchSynonymResponse 响应同义词组新增、更新和删除请求的JSON数据结构体
+type SearchSynonymResponse struct {
+	Data    SearchSynonymResultData `json:"data"`
+	Message string                  `json:"message"`
+}
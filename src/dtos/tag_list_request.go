@@ -0,0 +1,6 @@
+package dtos
+
+// TagListRequest 用于接收查询标签列表请求的数据结构体
+type TagListRequest struct {
+	Size int `form:"size" binding:"omitempty,min=1,max=1000"` // 返回标签数量，默认100
+}
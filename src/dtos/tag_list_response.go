@@ -0,0 +1,16 @@
+package dtos
+
+type TagCount struct {
+	Name  string `json:"name"`
+	Count int64  `json:"count"` // 使用该标签的文章数量
+}
+
+type TagListData struct {
+	List []TagCount `json:"list"`
+}
+
+// TagListResponse 响应查询标签列表请求的JSON数据结构体
+type TagListResponse struct {
+	Data    TagListData `json:"data"`
+	Message string      `json:"message"`
+}
@@ -0,0 +1,18 @@
+package dtos
+
+import "demo/src/models"
+
+type UploadResultData struct {
+	URL      string                 `json:"url"` // 图片访问地址，用于填写文章的picture字段
+	MimeType string                 `json:"mime_type"`
+	Size     int64                  `json:"size"`
+	Width    int                    `json:"width"`
+	Height   int                    `json:"height"`
+	Variants models.PictureVariants `json:"variants"` // 缩略图及不同宽度版本
+}
+
+// UploadResponse 响应图片上传请求的JSON数据结构体
+type UploadResponse struct {
+	Data    UploadResultData `json:"data"`
+	Message string           `json:"message"`
+}
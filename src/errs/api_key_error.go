@@ -0,0 +1,10 @@
+package errs
+
+import "errors"
+
+var (
+	// ErrAPIKeyNotFound API Key不存在
+	ErrAPIKeyNotFound = errors.New("api key not found")
+	// ErrInvalidAPIKey API Key无效或已吊销
+	ErrInvalidAPIKey = errors.New("invalid api key")
+)
@@ -0,0 +1,21 @@
+package errs
+
+import "errors"
+
+// ErrArticleNotFound 文章不存在
+var ErrArticleNotFound = errors.New("article not found")
+
+// ErrInvalidPublishTime 定时发布时间无效
+var ErrInvalidPublishTime = errors.New("publish_at must be in the future for scheduled articles")
+
+// ErrSlugTaken 访问标识已被其他文章使用
+var ErrSlugTaken = errors.New("slug is already taken by another article")
+
+// ErrEmptyBulk 批量写入的文章为空
+var ErrEmptyBulk = errors.New("bulk request contains no articles")
+
+// ErrTooManyBulkItems 批量写入的文章数量超过上限
+var ErrTooManyBulkItems = errors.New("bulk request contains too many articles")
+
+// ErrBulkBodyTooLarge 批量写入的请求体超过大小上限
+var ErrBulkBodyTooLarge = errors.New("bulk request body is too large")
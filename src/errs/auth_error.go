@@ -0,0 +1,34 @@
+package errs
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrUnauthenticated 需要登录的操作没有携带有效令牌
+	ErrUnauthenticated = errors.New("authentication required")
+	// ErrForbidden 没有操作该资源的权限
+	ErrForbidden = errors.New("permission denied")
+)
+
+// PermissionError 缺少某项权限，errors.Is可匹配ErrForbidden
+type PermissionError struct {
+	Permission string
+}
+
+func (e PermissionError) Error() string {
+	return fmt.Sprintf("permission denied: missing %s permission", e.Permission)
+}
+
+// Is 使PermissionError可以通过errors.Is(err, ErrForbidden)判断
+func (e PermissionError) Is(target error) bool {
+	return target == ErrForbidden
+}
+
+// NewPermissionError 创建PermissionError实例
+func NewPermissionError(permission string) PermissionError {
+	return PermissionError{
+		Permission: permission,
+	}
+}
@@ -0,0 +1,14 @@
+package errs
+
+import "errors"
+
+var (
+	// ErrCategoryNotFound 分类不存在
+	ErrCategoryNotFound = errors.New("category not found")
+	// ErrCategoryHasArticles 分类下还有文章，不能删除
+	ErrCategoryHasArticles = errors.New("category still has articles")
+	// ErrCategoryHasChildren 分类下还有子分类，不能删除
+	ErrCategoryHasChildren = errors.New("category still has child categories")
+	// ErrInvalidParentCategory 父分类无效（不存在、是自身或自身的子分类）
+	ErrInvalidParentCategory = errors.New("invalid parent category")
+)
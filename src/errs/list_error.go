@@ -0,0 +1,16 @@
+package errs
+
+import "errors"
+
+var (
+	// ErrInvalidCursor 分页游标无效或与当前排序不一致
+	ErrInvalidCursor = errors.New("invalid cursor")
+	// ErrPageTooDeep 页码超出ES的max_result_window，需要改用游标分页
+	ErrPageTooDeep = errors.New("page is too deep, use cursor pagination instead")
+	// ErrInvalidListField fields参数包含不支持的字段
+	ErrInvalidListField = errors.New("invalid list field")
+	// ErrInvalidFacet facets参数包含不支持的分面
+	ErrInvalidFacet = errors.New("invalid facet")
+	// ErrInvalidStatusFilter status参数包含不支持的文章状态
+	ErrInvalidStatusFilter = errors.New("invalid status filter")
+)
@@ -0,0 +1,6 @@
+package errs
+
+import "errors"
+
+// ErrRevisionNotFound 文章版本不存在
+var ErrRevisionNotFound = errors.New("revision not found")
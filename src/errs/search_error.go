@@ -0,0 +1,20 @@
+package errs
+
+import "errors"
+
+var (
+	// ErrSynonymNotFound 同义词组不存在
+	ErrSynonymNotFound = errors.New("synonym not found")
+	// ErrInvalidSynonym 同义词包含分隔符等不允许的字符
+	ErrInvalidSynonym = errors.New("synonym terms must not contain ',' or '=>'")
+	// ErrStopwordNotFound 停用词不存在
+	ErrStopwordNotFound = errors.New("stopword not found")
+	// ErrInvalidStopword 停用词包含同义词规则的分隔符
+	ErrInvalidStopword = errors.New("stopword must not contain ',' or '=>'")
+	// ErrStopwordExists 停用词已存在
+	ErrStopwordExists = errors.New("stopword already exists")
+	// ErrArticleIndexMissing 文章索引不存在，需要执行migrate-index命令创建
+	ErrArticleIndexMissing = errors.New("article index does not exist, run `demo migrate-index` to create it")
+	// ErrArticleIndexOutdated 文章索引的映射或分析器配置不是当前版本，需要执行migrate-index命令重建
+	ErrArticleIndexOutdated = errors.New("article index is outdated, run `demo migrate-index` to rebuild it")
+)
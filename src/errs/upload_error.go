@@ -0,0 +1,14 @@
+package errs
+
+import "errors"
+
+var (
+	// ErrUnsupportedFileType 不支持的上传文件类型
+	ErrUnsupportedFileType = errors.New("unsupported file type, only jpeg, png, gif and webp images are allowed")
+	// ErrFileTooLarge 上传文件超过大小限制
+	ErrFileTooLarge = errors.New("file too large")
+	// ErrImageTooLarge 图片尺寸超过限制
+	ErrImageTooLarge = errors.New("image dimensions too large")
+	// ErrPictureNotFound 文章图片指向本服务的存储，但存储中没有该文件
+	ErrPictureNotFound = errors.New("picture not found in storage, upload it first")
+)
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"demo/src/dtos"
+	"demo/src/errs"
+	"demo/src/services"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+type APIKeyHandler struct {
+	service *services.APIKeyService
+}
+
+func NewAPIKeyHandler(service *services.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		service: service,
+	}
+}
+
+// apiKeyErrorStatus 根据API Key错误类型返回HTTP状态码
+func apiKeyErrorStatus(err error) int {
+	if errors.Is(err, errs.ErrAPIKeyNotFound) {
+		return http.StatusNotFound
+	}
+	if status, ok := authErrorStatus(err); ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// AddAPIKey 处理创建API Key请求
+func (h *APIKeyHandler) AddAPIKey(c *gin.Context) {
+	var req dtos.APIKeyAddRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.service.AddAPIKey(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(apiKeyErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	response := dtos.APIKeyAddResponse{
+		Data:    *result,
+		Message: "API key created successfully. Store the key now, it will not be shown again.",
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ListAPIKeys 处理获取API Key列表请求
+func (h *APIKeyHandler) ListAPIKeys(c *gin.Context) {
+	apiKeys, err := h.service.ListAPIKeys(c.Request.Context())
+	if err != nil {
+		c.JSON(apiKeyErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	response := dtos.APIKeyListResponse{
+		Data: dtos.APIKeyListData{
+			List: apiKeys,
+		},
+		Message: "API keys fetched successfully",
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// RevokeAPIKey 处理吊销API Key请求
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	// 验证API Key ID
+	id, err := strconv.ParseUint(c.Param("api_key_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid API key ID"})
+		return
+	}
+
+	if err := h.service.RevokeAPIKey(c.Request.Context(), id); err != nil {
+		c.JSON(apiKeyErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	response := dtos.APIKeyRevokeResponse{
+		Data: dtos.APIKeyRevokeResultData{
+			APIKeyID: id,
+		},
+		Message: "API key revoked successfully.",
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -2,9 +2,12 @@ package handlers
 
 import (
 	"demo/src/dtos"
+	"demo/src/errs"
 	"demo/src/services"
+	"errors"
 	"github.com/gin-gonic/gin"
 	"net/http"
+	"net/url"
 	"strconv"
 )
 
@@ -18,6 +21,18 @@ func NewArticleHandler(service *services.ArticleService) *ArticleHandler {
 	}
 }
 
+// authErrorStatus 认证和权限错误对应的HTTP状态码
+func authErrorStatus(err error) (int, bool) {
+	switch {
+	case errors.Is(err, errs.ErrUnauthenticated):
+		return http.StatusUnauthorized, true
+	case errors.Is(err, errs.ErrForbidden):
+		return http.StatusForbidden, true
+	default:
+		return 0, false
+	}
+}
+
 // AddArticle 处理新增文章请求
 func (h *ArticleHandler) AddArticle(c *gin.Context) {
 	var articleReq dtos.ArticleAddRequest
@@ -30,6 +45,19 @@ func (h *ArticleHandler) AddArticle(c *gin.Context) {
 	ctx := c.Request.Context()
 	articleID, err := h.service.AddArticle(ctx, &articleReq)
 	if err != nil {
+		if errors.Is(err, errs.ErrCategoryNotFound) || errors.Is(err, errs.ErrInvalidPublishTime) ||
+			errors.Is(err, errs.ErrPictureNotFound) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, errs.ErrSlugTaken) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		if status, ok := authErrorStatus(err); ok {
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -44,6 +72,59 @@ func (h *ArticleHandler) AddArticle(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// GetArticle 处理获取文章详情请求
+func (h *ArticleHandler) GetArticle(c *gin.Context) {
+	// 验证文章ID
+	id, err := strconv.ParseUint(c.Param("article_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid article ID"})
+		return
+	}
+
+	// 获取文章详情
+	article, err := h.service.GetArticle(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, errs.ErrArticleNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := dtos.ArticleDetailResponse{
+		Data:    *article,
+		Message: "Article fetched successfully",
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetArticleBySlug 处理根据访问标识获取文章详情请求，历史访问标识永久跳转到当前访问标识
+func (h *ArticleHandler) GetArticleBySlug(c *gin.Context) {
+	article, redirectSlug, err := h.service.GetArticleBySlug(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		if errors.Is(err, errs.ErrArticleNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if redirectSlug != "" {
+		c.Redirect(http.StatusMovedPermanently, "/api/v1/article/by-slug/"+url.PathEscape(redirectSlug))
+		return
+	}
+
+	response := dtos.ArticleDetailResponse{
+		Data:    *article,
+		Message: "Article fetched successfully",
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
 // ListArticles 处理获取文章列表请求
 func (h *ArticleHandler) ListArticles(c *gin.Context) {
 	var req dtos.ArticleListRequest
@@ -53,8 +134,18 @@ func (h *ArticleHandler) ListArticles(c *gin.Context) {
 	}
 
 	// 获取文章列表
-	articles, err := h.service.ListArticles(c.Request.Context(), req.Page, req.PageSize, req.Sort, req.Order)
+	articles, err := h.service.ListArticles(c.Request.Context(), &req)
 	if err != nil {
+		if status, ok := authErrorStatus(err); ok {
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, errs.ErrInvalidCursor) || errors.Is(err, errs.ErrPageTooDeep) ||
+			errors.Is(err, errs.ErrInvalidListField) || errors.Is(err, errs.ErrInvalidFacet) ||
+			errors.Is(err, errs.ErrInvalidStatusFilter) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -62,6 +153,30 @@ func (h *ArticleHandler) ListArticles(c *gin.Context) {
 	c.JSON(http.StatusOK, articles)
 }
 
+// SuggestArticles 处理文章标题联想请求
+func (h *ArticleHandler) SuggestArticles(c *gin.Context) {
+	var req dtos.ArticleSuggestRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	suggestions, err := h.service.SuggestArticles(c.Request.Context(), req.Prefix, req.Size)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := dtos.ArticleSuggestResponse{
+		Data: dtos.ArticleSuggestData{
+			List: suggestions,
+		},
+		Message: "Suggestions fetched successfully",
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
 // UpdateArticle 处理更新文章请求
 func (h *ArticleHandler) UpdateArticle(c *gin.Context) {
 	// 获取文章ID
@@ -84,6 +199,18 @@ func (h *ArticleHandler) UpdateArticle(c *gin.Context) {
 	ctx := c.Request.Context()
 	err = h.service.UpdateArticle(ctx, id, &articleReq)
 	if err != nil {
+		if errors.Is(err, errs.ErrArticleNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, errs.ErrSlugTaken) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		if status, ok := authErrorStatus(err); ok {
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -97,3 +224,77 @@ func (h *ArticleHandler) UpdateArticle(c *gin.Context) {
 
 	c.JSON(http.StatusOK, response)
 }
+
+// PublishArticle 处理发布文章请求
+func (h *ArticleHandler) PublishArticle(c *gin.Context) {
+	// 验证文章ID
+	id, err := strconv.ParseUint(c.Param("article_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid article ID"})
+		return
+	}
+
+	// 请求体可选，为空时立即发布
+	var req dtos.ArticlePublishRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	// 发布文章
+	if err := h.service.PublishArticle(c.Request.Context(), id, req.PublishAt); err != nil {
+		if errors.Is(err, errs.ErrArticleNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if status, ok := authErrorStatus(err); ok {
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := dtos.ArticleUpdateResponse{
+		Data: dtos.ArticleUpdateResultData{
+			ArticleID: id,
+		},
+		Message: "Article published successfully.",
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// DeleteArticle 处理删除文章请求
+func (h *ArticleHandler) DeleteArticle(c *gin.Context) {
+	// 验证文章ID
+	id, err := strconv.ParseUint(c.Param("article_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid article ID"})
+		return
+	}
+
+	if err := h.service.DeleteArticle(c.Request.Context(), id); err != nil {
+		if errors.Is(err, errs.ErrArticleNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if status, ok := authErrorStatus(err); ok {
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := dtos.ArticleUpdateResponse{
+		Data: dtos.ArticleUpdateResultData{
+			ArticleID: id,
+		},
+		Message: "Article deleted successfully.",
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"bufio"
+	"demo/src/dtos"
+	"demo/src/errs"
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"io"
+	"net/http"
+)
+
+// articleErrorStatus 单篇文章写入错误对应的HTTP状态码，与新增和更新文章接口一致
+func articleErrorStatus(err error) int {
+	if status, ok := authErrorStatus(err); ok {
+		return status
+	}
+	switch {
+	case errors.Is(err, errs.ErrArticleNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, errs.ErrSlugTaken):
+		return http.StatusConflict
+	case errors.Is(err, errs.ErrCategoryNotFound), errors.Is(err, errs.ErrInvalidPublishTime), errors.Is(err, errs.ErrPictureNotFound):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// decodeBulkItems 逐篇解析批量写入的请求体，支持JSON数组和NDJSON（每行一篇文章），
+// 超过maxItems篇时立即停止读取并返回ErrTooManyBulkItems，不会缓存整个请求体
+func decodeBulkItems(body io.Reader, maxItems int) ([]dtos.ArticleBulkItem, error) {
+	reader := bufio.NewReader(body)
+	array, err := startsWithArray(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(reader)
+	if array {
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+	}
+
+	items := []dtos.ArticleBulkItem{}
+	for decoder.More() {
+		if len(items) == maxItems {
+			return nil, errs.ErrTooManyBulkItems
+		}
+		var item dtos.ArticleBulkItem
+		if err := decoder.Decode(&item); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	if array {
+		// 读取数组结束符，检查JSON是否完整
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+	}
+	return items, nil
+}
+
+// startsWithArray 跳过开头的空白，判断请求体是否为JSON数组
+func startsWithArray(reader *bufio.Reader) (bool, error) {
+	for {
+		b, err := reader.ReadByte()
+		if err == io.EOF {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		switch b {
+		case ' ', '\t', '\r', '\n':
+			continue
+		}
+		return b == '[', reader.UnreadByte()
+	}
+}
+
+// validateBulkItem 按单篇接口的规则校验：新增的文章使用新增文章的规则，更新的文章使用更新文章的规则
+func validateBulkItem(item *dtos.ArticleBulkItem) error {
+	if item.ArticleID > 0 {
+		return binding.Validator.ValidateStruct(item)
+	}
+	return binding.Validator.ValidateStruct(&dtos.ArticleAddRequest{
+		Title:         item.Title,
+		Slug:          item.Slug,
+		Picture:       item.Picture,
+		Content:       item.Content,
+		ContentFormat: item.ContentFormat,
+		Tags:          item.Tags,
+		CategoryID:    item.CategoryID,
+		Status:        item.Status,
+		PublishAt:     item.PublishAt,
+	})
+}
+
+// BulkSaveArticles 处理批量新增或更新文章请求，返回每篇文章的处理结果
+func (h *ArticleHandler) BulkSaveArticles(c *gin.Context) {
+	// 限制请求体大小，数量上限无法限制单篇文章的内容长度
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.service.BulkMaxBodySize())
+
+	items, err := decodeBulkItems(c.Request.Body, h.service.BulkMaxItems())
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		switch {
+		case errors.As(err, &maxBytesErr):
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": errs.ErrBulkBodyTooLarge.Error()})
+		case errors.Is(err, errs.ErrTooManyBulkItems):
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	if len(items) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errs.ErrEmptyBulk.Error()})
+		return
+	}
+
+	// 校验失败的文章单独返回错误，不影响其他文章
+	results := make([]dtos.ArticleBulkResult, len(items))
+	var valid []dtos.ArticleBulkItem
+	var positions []int
+	for i := range items {
+		results[i] = dtos.ArticleBulkResult{Index: i, ArticleID: items[i].ArticleID, Action: "created"}
+		if items[i].ArticleID > 0 {
+			results[i].Action = "updated"
+		}
+		if err := validateBulkItem(&items[i]); err != nil {
+			results[i].Status = http.StatusBadRequest
+			results[i].Error = err.Error()
+			continue
+		}
+		valid = append(valid, items[i])
+		positions = append(positions, i)
+	}
+
+	if len(valid) > 0 {
+		outcomes, err := h.service.BulkSaveArticles(c.Request.Context(), valid)
+		if err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, errs.ErrTooManyBulkItems) {
+				status = http.StatusRequestEntityTooLarge
+			} else if s, ok := authErrorStatus(err); ok {
+				status = s
+			}
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+
+		for i, outcome := range outcomes {
+			result := &results[positions[i]]
+			result.ArticleID = outcome.ArticleID
+			result.Status = http.StatusOK
+			if outcome.Err != nil {
+				result.Status = articleErrorStatus(outcome.Err)
+				result.Error = outcome.Err.Error()
+			}
+		}
+	}
+
+	response := dtos.ArticleBulkResponse{
+		Data:    dtos.ArticleBulkData{Results: results},
+		Message: "Articles saved.",
+	}
+	for _, result := range results {
+		if result.Error == "" {
+			response.Data.Succeeded++
+		} else {
+			response.Data.Failed++
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
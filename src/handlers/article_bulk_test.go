@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"demo/src/errs"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDecodeBulkItems(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		max     int
+		wantIDs []uint64
+		wantErr error
+	}{
+		{name: "array", body: ` [{"article_id":1,"title":"a"},{"title":"b"}]`, max: 10, wantIDs: []uint64{1, 0}},
+		{name: "ndjson", body: "{\"article_id\":3,\"title\":\"a\"}\n\n{\"article_id\":4,\"title\":\"b\"}\n", max: 10, wantIDs: []uint64{3, 4}},
+		{name: "empty array", body: `[]`, max: 10, wantIDs: []uint64{}},
+		{name: "empty body", body: "  \n", max: 10, wantIDs: []uint64{}},
+		{name: "array at limit", body: `[{"title":"a"},{"title":"b"}]`, max: 2, wantIDs: []uint64{0, 0}},
+		{name: "array over limit", body: `[{"title":"a"},{"title":"b"},{"title":"c"}`, max: 2, wantErr: errs.ErrTooManyBulkItems},
+		{name: "ndjson over limit", body: "{\"title\":\"a\"}\n{\"title\":\"b\"}\n", max: 1, wantErr: errs.ErrTooManyBulkItems},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := decodeBulkItems(strings.NewReader(tt.body), tt.max)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("decodeBulkItems() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decodeBulkItems() error = %v", err)
+			}
+			if len(items) != len(tt.wantIDs) {
+				t.Fatalf("decodeBulkItems() returned %d items, want %d", len(items), len(tt.wantIDs))
+			}
+			for i, item := range items {
+				if item.ArticleID != tt.wantIDs[i] {
+					t.Errorf("item %d article_id = %d, want %d", i, item.ArticleID, tt.wantIDs[i])
+				}
+			}
+		})
+	}
+
+	if _, err := decodeBulkItems(strings.NewReader(`[{"title":"a"}`), 10); err == nil {
+		t.Error("decodeBulkItems() accepted an unterminated array")
+	}
+}
+
+func TestDecodeBulkItemsBodyTooLarge(t *testing.T) {
+	body := `[{"title":"a","content":"` + strings.Repeat("x", 100) + `"}]`
+	limited := http.MaxBytesReader(httptest.NewRecorder(), io.NopCloser(strings.NewReader(body)), 64)
+
+	_, err := decodeBulkItems(limited, 10)
+	var maxBytesErr *http.MaxBytesError
+	if !errors.As(err, &maxBytesErr) {
+		t.Fatalf("decodeBulkItems() error = %v, want *http.MaxBytesError", err)
+	}
+}
+
+func TestValidateBulkItemUsesAddRulesForCreates(t *testing.T) {
+	items, err := decodeBulkItems(strings.NewReader(`[{"title":"a","status":"archived"},{"article_id":1,"title":"a","status":"archived"}]`), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := validateBulkItem(&items[0]); err == nil {
+		t.Error("validateBulkItem() accepted status archived for a new article")
+	}
+	if err := validateBulkItem(&items[1]); err != nil {
+		t.Errorf("validateBulkItem() rejected status archived for an update: %v", err)
+	}
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"demo/src/dtos"
+	"demo/src/errs"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+// RelatedArticles 处理获取相关文章请求
+func (h *ArticleHandler) RelatedArticles(c *gin.Context) {
+	// 验证文章ID
+	id, err := strconv.ParseUint(c.Param("article_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid article ID"})
+		return
+	}
+
+	var req dtos.ArticleRelatedRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	articles, err := h.service.RelatedArticles(c.Request.Context(), id, req.Size)
+	if err != nil {
+		if errors.Is(err, errs.ErrArticleNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := dtos.ArticleRelatedResponse{
+		Data: dtos.ArticleRelatedData{
+			List: articles,
+		},
+		Message: "Related articles fetched successfully",
+	}
+
+	c.JSON(http.StatusOK, response)
+}
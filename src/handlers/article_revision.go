@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"demo/src/dtos"
+	"demo/src/errs"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+// revisionErrorStatus 根据文章版本错误类型返回HTTP状态码，恢复版本时的写入错误与更新文章接口一致
+func revisionErrorStatus(err error) int {
+	if status, ok := authErrorStatus(err); ok {
+		return status
+	}
+	switch {
+	case errors.Is(err, errs.ErrArticleNotFound), errors.Is(err, errs.ErrRevisionNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, errs.ErrSlugTaken):
+		return http.StatusConflict
+	case errors.Is(err, errs.ErrCategoryNotFound), errors.Is(err, errs.ErrPictureNotFound):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// ListRevisions 处理获取文章版本列表请求
+func (h *ArticleHandler) ListRevisions(c *gin.Context) {
+	// 验证文章ID
+	id, err := strconv.ParseUint(c.Param("article_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid article ID"})
+		return
+	}
+
+	revisions, err := h.service.ListRevisions(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(revisionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	response := dtos.ArticleRevisionListResponse{
+		Data: dtos.ArticleRevisionListData{
+			List: revisions,
+		},
+		Message: "Revisions fetched successfully",
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetRevision 处理获取文章版本详情请求
+func (h *ArticleHandler) GetRevision(c *gin.Context) {
+	// 验证文章ID和版本号
+	id, err := strconv.ParseUint(c.Param("article_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid article ID"})
+		return
+	}
+	revision, err := strconv.Atoi(c.Param("revision"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid revision"})
+		return
+	}
+
+	articleRevision, err := h.service.GetRevision(c.Request.Context(), id, revision)
+	if err != nil {
+		c.JSON(revisionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	response := dtos.ArticleRevisionResponse{
+		Data:    *articleRevision,
+		Message: "Revision fetched successfully",
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// DiffRevisions 处理比较文章版本请求
+func (h *ArticleHandler) DiffRevisions(c *gin.Context) {
+	// 验证文章ID
+	id, err := strconv.ParseUint(c.Param("article_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid article ID"})
+		return
+	}
+
+	var req dtos.ArticleRevisionDiffRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	diff, err := h.service.DiffRevisions(c.Request.Context(), id, &req)
+	if err != nil {
+		c.JSON(revisionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	response := dtos.ArticleRevisionDiffResponse{
+		Data:    *diff,
+		Message: "Revisions compared successfully",
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// RollbackArticle 处理恢复文章版本请求
+func (h *ArticleHandler) RollbackArticle(c *gin.Context) {
+	// 验证文章ID和版本号
+	id, err := strconv.ParseUint(c.Param("article_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid article ID"})
+		return
+	}
+	revision, err := strconv.Atoi(c.Param("revision"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid revision"})
+		return
+	}
+
+	// 恢复文章版本
+	if err := h.service.RollbackArticle(c.Request.Context(), id, revision); err != nil {
+		c.JSON(revisionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	response := dtos.ArticleUpdateResponse{
+		Data: dtos.ArticleUpdateResultData{
+			ArticleID: id,
+		},
+		Message: "Article rolled back successfully.",
+	}
+
+	c.JSON(http.StatusOK, response)
+}
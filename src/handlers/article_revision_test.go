@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"demo/src/errs"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestRevisionErrorStatus(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{err: errs.ErrArticleNotFound, want: http.StatusNotFound},
+		{err: errs.ErrRevisionNotFound, want: http.StatusNotFound},
+		{err: errs.ErrUnauthenticated, want: http.StatusUnauthorized},
+		{err: fmt.Errorf("%w: edit", errs.ErrForbidden), want: http.StatusForbidden},
+		{err: errs.ErrSlugTaken, want: http.StatusConflict},
+		{err: errs.ErrCategoryNotFound, want: http.StatusBadRequest},
+		{err: errors.New("connection refused"), want: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		if got := revisionErrorStatus(tt.err); got != tt.want {
+			t.Errorf("revisionErrorStatus(%v) = %d, want %d", tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"demo/src/dtos"
+	"demo/src/errs"
+	"demo/src/services"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+type CategoryHandler struct {
+	service *services.CategoryService
+}
+
+func NewCategoryHandler(service *services.CategoryService) *CategoryHandler {
+	return &CategoryHandler{
+		service: service,
+	}
+}
+
+// categoryErrorStatus 根据分类错误类型返回HTTP状态码
+func categoryErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, errs.ErrCategoryNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, errs.ErrInvalidParentCategory):
+		return http.StatusBadRequest
+	case errors.Is(err, errs.ErrCategoryHasArticles), errors.Is(err, errs.ErrCategoryHasChildren):
+		return http.StatusConflict
+	case errors.Is(err, errs.ErrUnauthenticated):
+		return http.StatusUnauthorized
+	case errors.Is(err, errs.ErrForbidden):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// AddCategory 处理新增分类请求
+func (h *CategoryHandler) AddCategory(c *gin.Context) {
+	var req dtos.CategoryAddRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 新增分类
+	categoryID, err := h.service.AddCategory(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(categoryErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	response := dtos.CategoryAddResponse{
+		Data: dtos.CategoryAddResultData{
+			CategoryID: categoryID,
+		},
+		Message: "Category added successfully.",
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ListCategories 处理获取分类树请求
+func (h *CategoryHandler) ListCategories(c *gin.Context) {
+	categories, err := h.service.ListCategories(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := dtos.CategoryListResponse{
+		Data: dtos.CategoryListData{
+			List: categories,
+		},
+		Message: "Categories fetched successfully",
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetCategory 处理获取分类详情请求
+func (h *CategoryHandler) GetCategory(c *gin.Context) {
+	// 验证分类ID
+	id, err := strconv.ParseUint(c.Param("category_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+		return
+	}
+
+	category, err := h.service.GetCategory(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(categoryErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	response := dtos.CategoryDetailResponse{
+		Data:    *category,
+		Message: "Category fetched successfully",
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// UpdateCategory 处理更新分类请求
+func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
+	// 验证分类ID
+	id, err := strconv.ParseUint(c.Param("category_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+		return
+	}
+
+	var req dtos.CategoryUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 更新分类
+	if err := h.service.UpdateCategory(c.Request.Context(), id, &req); err != nil {
+		c.JSON(categoryErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	response := dtos.CategoryUpdateResponse{
+		Data: dtos.CategoryUpdateResultData{
+			CategoryID: id,
+		},
+		Message: "Category updated successfully.",
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// DeleteCategory 处理删除分类请求
+func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
+	// 验证分类ID
+	id, err := strconv.ParseUint(c.Param("category_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+		return
+	}
+
+	// 删除分类
+	if err := h.service.DeleteCategory(c.Request.Context(), id); err != nil {
+		c.JSON(categoryErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	response := dtos.CategoryUpdateResponse{
+		Data: dtos.CategoryUpdateResultData{
+			CategoryID: id,
+		},
+		Message: "Category deleted successfully.",
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"demo/src/dtos"
+	"demo/src/errs"
+	"demo/src/services"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+)
+
+// exportContentTypes 导出格式对应的Content-Type
+var exportContentTypes = map[string]string{
+	services.ExportFormatNDJSON: "application/x-ndjson",
+	services.ExportFormatCSV:    "text/csv; charset=utf-8",
+	services.ExportFormatJSON:   "application/json; charset=utf-8",
+}
+
+type ExportHandler struct {
+	service *services.ExportService
+}
+
+func NewExportHandler(service *services.ExportService) *ExportHandler {
+	return &ExportHandler{
+		service: service,
+	}
+}
+
+// ExportArticles 处理导出文章请求，以分块传输的方式流式返回
+func (h *ExportHandler) ExportArticles(c *gin.Context) {
+	var req dtos.ArticleExportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Format == "" {
+		req.Format = services.ExportFormatNDJSON
+	}
+
+	c.Header("Content-Type", exportContentTypes[req.Format])
+	c.Header("Content-Disposition", `attachment; filename="articles.`+req.Format+`"`)
+	c.Status(http.StatusOK)
+
+	if err := h.service.ExportArticles(c.Request.Context(), c.Writer, &req); err != nil {
+		// 已开始输出时无法再返回错误响应，只能中断
+		if c.Writer.Written() {
+			log.Printf("Article export aborted: %v", err)
+			return
+		}
+		c.Writer.Header().Del("Content-Type")
+		c.Writer.Header().Del("Content-Disposition")
+		status := http.StatusInternalServerError
+		if s, ok := authErrorStatus(err); ok {
+			status = s
+		} else if errors.Is(err, errs.ErrInvalidStatusFilter) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+	}
+}
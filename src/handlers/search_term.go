@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"demo/src/dtos"
+	"demo/src/errs"
+	"demo/src/services"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+type SearchTermHandler struct {
+	service *services.SearchTermService
+}
+
+func NewSearchTermHandler(service *services.SearchTermService) *SearchTermHandler {
+	return &SearchTermHandler{
+		service: service,
+	}
+}
+
+// searchTermErrorStatus 根据同义词和停用词错误类型返回HTTP状态码
+func searchTermErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, errs.ErrSynonymNotFound), errors.Is(err, errs.ErrStopwordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, errs.ErrInvalidSynonym), errors.Is(err, errs.ErrInvalidStopword):
+		return http.StatusBadRequest
+	case errors.Is(err, errs.ErrStopwordExists):
+		return http.StatusConflict
+	case errors.Is(err, errs.ErrUnauthenticated):
+		return http.StatusUnauthorized
+	case errors.Is(err, errs.ErrForbidden):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// ListSynonyms 处理获取同义词组列表请求
+func (h *SearchTermHandler) ListSynonyms(c *gin.Context) {
+	synonyms, err := h.service.ListSynonyms(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := dtos.SearchSynonymListResponse{
+		Data: dtos.SearchSynonymListData{
+			List: synonyms,
+		},
+		Message: "Synonyms fetched successfully",
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// AddSynonym 处理新增同义词组请求
+func (h *SearchTermHandler) AddSynonym(c *gin.Context) {
+	var req dtos.SearchSynonymRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	synonymID, err := h.service.AddSynonym(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(searchTermErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	response := dtos.SearchSynonymResponse{
+		Data: dtos.SearchSynonymResultData{
+			SynonymID: synonymID,
+		},
+		Message: "Synonym added successfully.",
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// UpdateSynonym 处理更新同义词组请求
+func (h *SearchTermHandler) UpdateSynonym(c *gin.Context) {
+	// 验证同义词组ID
+	id, err := strconv.ParseUint(c.Param("synonym_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid synonym ID"})
+		return
+	}
+
+	var req dtos.SearchSynonymRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.UpdateSynonym(c.Request.Context(), id, &req); err != nil {
+		c.JSON(searchTermErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	response := dtos.SearchSynonymResponse{
+		Data: dtos.SearchSynonymResultData{
+			SynonymID: id,
+		},
+		Message: "Synonym updated successfully.",
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// DeleteSynonym 处理删除同义词组请求
+func (h *SearchTermHandler) DeleteSynonym(c *gin.Context) {
+	// 验证同义词组ID
+	id, err := strconv.ParseUint(c.Param("synonym_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid synonym ID"})
+		return
+	}
+
+	if err := h.service.DeleteSynonym(c.Request.Context(), id); err != nil {
+		c.JSON(searchTermErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	response := dtos.SearchSynonymResponse{
+		Data: dtos.SearchSynonymResultData{
+			SynonymID: id,
+		},
+		Message: "Synonym deleted successfully.",
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ListStopwords 处理获取停用词列表请求
+func (h *SearchTermHandler) ListStopwords(c *gin.Context) {
+	stopwords, err := h.service.ListStopwords(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := dtos.SearchStopwordListResponse{
+		Data: dtos.SearchStopwordListData{
+			List: stopwords,
+		},
+		Message: "Stopwords fetched successfully",
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// AddStopword 处理新增停用词请求
+func (h *SearchTermHandler) AddStopword(c *gin.Context) {
+	var req dtos.SearchStopwordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	stopwordID, err := h.service.AddStopword(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(searchTermErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	response := dtos.SearchStopwordResponse{
+		Data: dtos.SearchStopwordResultData{
+			StopwordID: stopwordID,
+		},
+		Message: "Stopword added successfully.",
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// DeleteStopword 处理删除停用词请求
+func (h *SearchTermHandler) DeleteStopword(c *gin.Context) {
+	// 验证停用词ID
+	id, err := strconv.ParseUint(c.Param("stopword_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid stopword ID"})
+		return
+	}
+
+	if err := h.service.DeleteStopword(c.Request.Context(), id); err != nil {
+		c.JSON(searchTermErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	response := dtos.SearchStopwordResponse{
+		Data: dtos.SearchStopwordResultData{
+			StopwordID: id,
+		},
+		Message: "Stopword deleted successfully.",
+	}
+
+	c.JSON(http.StatusOK, response)
+}
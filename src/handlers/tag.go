@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"demo/src/dtos"
+	"demo/src/services"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type TagHandler struct {
+	service *services.TagService
+}
+
+func NewTagHandler(service *services.TagService) *TagHandler {
+	return &TagHandler{
+		service: service,
+	}
+}
+
+// ListTags 处理获取标签列表请求
+func (h *TagHandler) ListTags(c *gin.Context) {
+	var req dtos.TagListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 获取标签列表
+	tags, err := h.service.ListTags(c.Request.Context(), req.Size)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := dtos.TagListResponse{
+		Data: dtos.TagListData{
+			List: tags,
+		},
+		Message: "Tags fetched successfully",
+	}
+
+	c.JSON(http.StatusOK, response)
+}
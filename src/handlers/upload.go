@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"demo/src/dtos"
+	"demo/src/errs"
+	"demo/src/services"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// multipartOverhead multipart请求中除文件内容外的额外空间（边界、表单头等）
+const multipartOverhead = 1 << 20
+
+type UploadHandler struct {
+	service *services.UploadService
+}
+
+func NewUploadHandler(service *services.UploadService) *UploadHandler {
+	return &UploadHandler{
+		service: service,
+	}
+}
+
+// UploadImage 处理图片上传请求
+func (h *UploadHandler) UploadImage(c *gin.Context) {
+	// 限制请求体大小，避免超大文件占用磁盘和内存
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.service.MaxSize()+multipartOverhead)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": errs.ErrFileTooLarge.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	// 上传图片
+	result, err := h.service.UploadImage(c.Request.Context(), file)
+	if err != nil {
+		if status, ok := authErrorStatus(err); ok {
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+		switch {
+		case errors.Is(err, errs.ErrFileTooLarge):
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+		case errors.Is(err, errs.ErrUnsupportedFileType):
+			c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+		case errors.Is(err, errs.ErrImageTooLarge):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	response := dtos.UploadResponse{
+		Data:    *result,
+		Message: "Image uploaded successfully.",
+	}
+
+	c.JSON(http.StatusOK, response)
+}
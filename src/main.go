@@ -1,6 +1,9 @@
 package main
 
 import (
+	"context"
+	"demo/src/common/auth"
+	"demo/src/middlewares"
 	"demo/src/repositories"
 	"demo/src/services"
 	"github.com/gin-gonic/gin"
@@ -17,6 +20,12 @@ func main() {
 		log.Fatalf("Error loading .env file: %v", err)
 	}
 
+	// 带子命令时执行命令行工具（如demo export），否则启动HTTP服务
+	if len(os.Args) > 1 {
+		runCommand(os.Args[1], os.Args[2:])
+		return
+	}
+
 	// 初始化数据库连接
 	db := repositories.InitDB()
 
@@ -26,8 +35,34 @@ func main() {
 	// 初始化 Redis 连接
 	rdb := repositories.InitRedis()
 
+	// 初始化文件存储
+	storage := repositories.InitStorage()
+
+	// 初始化JWT校验和角色权限策略
+	verifier := auth.InitVerifier()
+	policy := auth.InitPolicy()
+
+	// 初始化限流配置
+	rateLimitRules := middlewares.InitRateLimitRules()
+
 	// 创建服务层实例
-	articleService := services.NewArticleService(db, esClient, rdb)
+	uploadService := services.NewUploadService(storage, policy)
+	categoryService := services.NewCategoryService(db, esClient, policy)
+	userService := services.NewUserService(db, esClient)
+	articleService := services.NewArticleService(db, esClient, rdb, uploadService, categoryService, userService, policy)
+	tagService := services.NewTagService(esClient)
+	searchTermService := services.NewSearchTermService(db, esClient, policy)
+	apiKeyService := services.NewAPIKeyService(db, rdb, userService, policy)
+	exportService := services.NewExportService(db, esClient, policy)
+
+	// 只检查文章索引版本，索引的创建和重建由migrate-index命令完成，避免多个实例启动时同时修改索引
+	if err := articleService.CheckArticleIndex(context.Background()); err != nil {
+		log.Printf("Article index check failed: %v", err)
+	}
+
+	// 启动定时发布任务，多实例部署时通过Redis选主只有一个实例执行
+	scheduler := services.NewPublishScheduler(db, esClient, rdb)
+	go scheduler.Run(context.Background())
 
 	// 设置日志
 	f, _ := os.Create("logs/gin.log")
@@ -35,7 +70,7 @@ func main() {
 	gin.DefaultErrorWriter = io.MultiWriter(f, os.Stderr)
 
 	// 使用router.go中的SetupRouter函数设置Gin路由
-	router := SetupRouter(articleService)
+	router := SetupRouter(articleService, tagService, categoryService, uploadService, searchTermService, apiKeyService, exportService, storage, verifier, repositories.NewRedisRepository(rdb), rateLimitRules)
 
 	port := os.Getenv("SERVER_PORT")
 	if port == "" {
@@ -0,0 +1,68 @@
+package middlewares
+
+import (
+	"context"
+	"demo/src/common/auth"
+	"demo/src/errs"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strings"
+)
+
+// APIKeyVerifier 校验API Key并返回调用方
+type APIKeyVerifier interface {
+	VerifyAPIKey(ctx context.Context, key string) (*auth.Principal, error)
+}
+
+// Auth 认证中间件，支持Authorization: Bearer <JWT>和Authorization: ApiKey <key>；
+// 携带凭证时必须校验通过，未携带凭证时只允许匿名访问GET等只读请求
+func Auth(verifier *auth.Verifier, apiKeys APIKeyVerifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		if header == "" {
+			if isReadOnly(c.Request.Method) {
+				c.Next()
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "authentication required"})
+			return
+		}
+
+		scheme, credential, _ := strings.Cut(header, " ")
+		credential = strings.TrimSpace(credential)
+		if credential == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid authorization header"})
+			return
+		}
+
+		var principal *auth.Principal
+		var err error
+		switch {
+		case strings.EqualFold(scheme, "Bearer"):
+			principal, err = verifier.Verify(credential)
+		case strings.EqualFold(scheme, "ApiKey"):
+			principal, err = apiKeys.VerifyAPIKey(c.Request.Context(), credential)
+		default:
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid authorization header"})
+			return
+		}
+		if err != nil {
+			status := http.StatusUnauthorized
+			if !errors.Is(err, auth.ErrInvalidToken) && !errors.Is(err, errs.ErrInvalidAPIKey) {
+				status = http.StatusInternalServerError
+			}
+			c.AbortWithStatusJSON(status, gin.H{"error": err.Error()})
+			return
+		}
+
+		// 保存到请求的context中，服务层通过auth.FromContext获取
+		c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), principal))
+		c.Next()
+	}
+}
+
+// isReadOnly 判断是否为只读请求
+func isReadOnly(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
@@ -0,0 +1,165 @@
+package middlewares
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"demo/src/common/auth"
+	"demo/src/common/env"
+	"demo/src/repositories"
+	"encoding/hex"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// maxIdempotencyKeyLength Idempotency-Key的最大长度
+const maxIdempotencyKeyLength = 255
+
+// responseRecorder 记录写出的响应内容
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Idempotency 幂等中间件，请求携带Idempotency-Key时，同一调用方使用相同Key的重试直接重放首次请求的响应；
+// Key对应的请求体不同或首次请求仍在处理中时返回409，首次请求返回5xx时不保存响应，允许重试。
+// 处理中的记录每隔IDEMPOTENCY_LOCK_TTL的三分之一续期一次，实例异常退出后最多IDEMPOTENCY_LOCK_TTL即可重试
+func Idempotency(store *repositories.RedisRepository) gin.HandlerFunc {
+	ttl := env.Duration("IDEMPOTENCY_TTL", 24*time.Hour)
+	lockTTL := env.Duration("IDEMPOTENCY_LOCK_TTL", time.Minute)
+	maxBodySize := env.Int64("IDEMPOTENCY_MAX_BODY_SIZE", 10<<20)
+
+	return func(c *gin.Context) {
+		idempotencyKey := c.GetHeader("Idempotency-Key")
+		if idempotencyKey == "" {
+			c.Next()
+			return
+		}
+		if len(idempotencyKey) > maxIdempotencyKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Idempotency-Key is too long"})
+			return
+		}
+
+		// 需要读取完整的请求体计算指纹，限制大小避免占用过多内存
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxBodySize))
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": "request body is too large"})
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		// Key按调用方和路由隔离，指纹区分同一Key下不同的请求体
+		identity := "ip:" + c.ClientIP()
+		if principal := auth.FromContext(c.Request.Context()); principal != nil {
+			identity = "sub:" + principal.Subject
+		}
+		key := identity + ":" + c.Request.Method + " " + c.FullPath() + ":" + idempotencyKey
+		sum := sha256.Sum256(append([]byte(c.Request.Method+" "+c.Request.URL.RequestURI()+"\n"), body...))
+		fingerprint := hex.EncodeToString(sum[:])
+
+		ctx := c.Request.Context()
+		started, record, err := store.StartIdempotentRequest(ctx, key, fingerprint, lockTTL)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if !started {
+			switch {
+			case record != nil && record.Fingerprint != fingerprint:
+				c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "Idempotency-Key was already used with a different request"})
+			case record == nil || !record.Completed:
+				c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "a request with this Idempotency-Key is still in progress"})
+			default:
+				c.Header("Idempotent-Replayed", "true")
+				c.Data(record.Status, record.ContentType, record.Body)
+				c.Abort()
+			}
+			return
+		}
+
+		stopRenew := renewInProgress(ctx, store, key, fingerprint, lockTTL)
+		defer stopRenew()
+		// 处理过程中panic时释放Key允许重试，再交给Recovery中间件处理
+		defer func() {
+			if r := recover(); r != nil {
+				stopRenew()
+				if err := store.DeleteIdempotentRequest(context.WithoutCancel(ctx), key); err != nil {
+					log.Printf("Failed to release idempotency key: %v", err)
+				}
+				panic(r)
+			}
+		}()
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+		stopRenew()
+
+		// 客户端超时断开后仍需保存结果，供其重试时重放
+		ctx = context.WithoutCancel(ctx)
+		if recorder.Status() >= http.StatusInternalServerError {
+			if err := store.DeleteIdempotentRequest(ctx, key); err != nil {
+				log.Printf("Failed to release idempotency key: %v", err)
+			}
+			return
+		}
+		if err := store.SaveIdempotentResponse(ctx, key, &repositories.IdempotencyRecord{
+			Fingerprint: fingerprint,
+			Completed:   true,
+			Status:      recorder.Status(),
+			ContentType: recorder.Header().Get("Content-Type"),
+			Body:        recorder.body.Bytes(),
+		}, ttl); err != nil {
+			log.Printf("Failed to save idempotent response: %v", err)
+		}
+	}
+}
+
+// renewInProgress 在请求处理期间定期续期处理中的记录，避免处理时间超过锁有效期时重试请求被重复执行，返回停止续期的函数，可以多次调用
+func renewInProgress(ctx context.Context, store *repositories.RedisRepository, key, fingerprint string, lockTTL time.Duration) func() {
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(lockTTL / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := store.RenewIdempotentRequest(ctx, key, fingerprint, lockTTL); err != nil && ctx.Err() == nil {
+					log.Printf("Failed to renew idempotency key: %v", err)
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			cancel()
+			<-done
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package middlewares
+
+import (
+	"demo/src/common/auth"
+	"demo/src/common/env"
+	"demo/src/repositories"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateLimitRule 限流规则：Period内最多Limit次请求
+type RateLimitRule struct {
+	Limit  int
+	Period time.Duration
+}
+
+// RateLimitRules 限流配置，按"方法 路由"匹配单独的规则，未匹配时按读写区分使用默认规则；
+// IP为认证之前按客户端IP对全部请求的总限制，限制无效凭证的尝试次数
+type RateLimitRules struct {
+	IP     RateLimitRule
+	Read   RateLimitRule
+	Write  RateLimitRule
+	Routes map[string]RateLimitRule // key如"POST /api/v1/uploads"
+}
+
+// parseRateLimitRule 解析"次数/时间段"格式的规则，如60/1m
+func parseRateLimitRule(value string) (RateLimitRule, error) {
+	limit, period, found := strings.Cut(strings.TrimSpace(value), "/")
+	if !found {
+		return RateLimitRule{}, fmt.Errorf("invalid rate limit %q, expected <limit>/<period>", value)
+	}
+	n, err := strconv.Atoi(limit)
+	if err != nil || n <= 0 {
+		return RateLimitRule{}, fmt.Errorf("invalid rate limit %q: limit must be a positive integer", value)
+	}
+	d, err := time.ParseDuration(period)
+	if err != nil || d <= 0 {
+		return RateLimitRule{}, fmt.Errorf("invalid rate limit %q: period must be a positive duration", value)
+	}
+	return RateLimitRule{Limit: n, Period: d}, nil
+}
+
+// InitRateLimitRules 从环境变量读取限流配置，RATE_LIMIT_ROUTES格式为"POST /api/v1/uploads=10/1m;GET /api/v1/articles=600/1m"
+func InitRateLimitRules() *RateLimitRules {
+	ip, err := parseRateLimitRule(env.String("RATE_LIMIT_IP", "600/1m"))
+	if err != nil {
+		log.Fatalf("Invalid RATE_LIMIT_IP: %v", err)
+	}
+	read, err := parseRateLimitRule(env.String("RATE_LIMIT_READ", "300/1m"))
+	if err != nil {
+		log.Fatalf("Invalid RATE_LIMIT_READ: %v", err)
+	}
+	write, err := parseRateLimitRule(env.String("RATE_LIMIT_WRITE", "60/1m"))
+	if err != nil {
+		log.Fatalf("Invalid RATE_LIMIT_WRITE: %v", err)
+	}
+
+	rules := &RateLimitRules{IP: ip, Read: read, Write: write, Routes: map[string]RateLimitRule{}}
+	for _, item := range strings.Split(env.String("RATE_LIMIT_ROUTES", ""), ";") {
+		if strings.TrimSpace(item) == "" {
+			continue
+		}
+		route, value, found := strings.Cut(item, "=")
+		if !found {
+			log.Fatalf("Invalid RATE_LIMIT_ROUTES item %q, expected <METHOD> <path>=<limit>/<period>", item)
+		}
+		rule, err := parseRateLimitRule(value)
+		if err != nil {
+			log.Fatalf("Invalid RATE_LIMIT_ROUTES: %v", err)
+		}
+		rules.Routes[strings.Join(strings.Fields(route), " ")] = rule
+	}
+	return rules
+}
+
+// rule 获取请求对应的限流规则
+func (r *RateLimitRules) rule(method, route string) RateLimitRule {
+	if rule, ok := r.Routes[method+" "+route]; ok {
+		return rule
+	}
+	if isReadOnly(method) {
+		return r.Read
+	}
+	return r.Write
+}
+
+// IPRateLimit 按客户端IP限制全部请求的总次数，需在Auth之前使用：
+// 携带无效JWT或API Key的请求在认证失败前也会计数，避免无限制地尝试凭证和查询数据库
+func IPRateLimit(limiter *repositories.RedisRepository, rules *RateLimitRules) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !allowRequest(c, limiter, "ip:"+c.ClientIP(), rules.IP, false) {
+			return
+		}
+		c.Next()
+	}
+}
+
+// RateLimit 基于Redis的分布式限流中间件，需在Auth之后使用：
+// 已认证的请求按调用方（用户或API Key）限流，匿名请求按客户端IP限流，每个路由单独计数
+func RateLimit(limiter *repositories.RedisRepository, rules *RateLimitRules) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.FullPath()
+		if route == "" {
+			c.Next() // 未匹配路由，交给404处理
+			return
+		}
+
+		identity := "ip:" + c.ClientIP()
+		if principal := auth.FromContext(c.Request.Context()); principal != nil {
+			identity = "sub:" + principal.Subject
+		}
+
+		rule := rules.rule(c.Request.Method, route)
+		if !allowRequest(c, limiter, identity+":"+c.Request.Method+" "+route, rule, true) {
+			return
+		}
+		c.Next()
+	}
+}
+
+// allowRequest 按规则计数并判断是否放行，超过限制时返回429；headers为true时返回RateLimit-*响应头
+func allowRequest(c *gin.Context, limiter *repositories.RedisRepository, key string, rule RateLimitRule, headers bool) bool {
+	result, err := limiter.AllowRequest(c.Request.Context(), key, rule.Limit, rule.Period)
+	if err != nil {
+		// Redis不可用时不限流，避免影响正常请求
+		log.Printf("Rate limiter unavailable: %v", err)
+		return true
+	}
+
+	if headers {
+		c.Header("RateLimit-Limit", strconv.Itoa(rule.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+	}
+	if !result.Allowed {
+		c.Header("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "rate limit exceeded"})
+		return false
+	}
+	return true
+}
+
+// ceilSeconds 将时间向上取整为秒
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
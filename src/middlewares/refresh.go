@@ -0,0 +1,26 @@
+package middlewares
+
+import (
+	"demo/src/repositories"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// Refresh 读取写请求的refresh参数（true、wait_for、false），覆盖本次请求ES写入的刷新策略，
+// 如编辑保存后需要立即在列表中看到修改时使用?refresh=wait_for
+func Refresh() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		mode := c.Query("refresh")
+		if mode == "" || isReadOnly(c.Request.Method) {
+			c.Next()
+			return
+		}
+		if !repositories.ValidRefreshMode(mode) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "refresh must be true, wait_for or false"})
+			return
+		}
+
+		c.Request = c.Request.WithContext(repositories.WithRefresh(c.Request.Context(), mode))
+		c.Next()
+	}
+}
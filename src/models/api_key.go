@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// APIKey 映射api_key数据表的结构体，只保存密钥的SHA-256哈希
+type APIKey struct {
+	ID         uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name       string     `gorm:"type:varchar(64)" json:"name"`
+	Prefix     string     `gorm:"type:varchar(16)" json:"prefix"`     // 密钥前几位，便于识别
+	KeyHash    string     `gorm:"type:char(64);uniqueIndex" json:"-"` // 密钥的SHA-256哈希
+	Scopes     StringList `gorm:"type:json" json:"scopes"`            // 权限范围：articles:read、articles:write、admin
+	CreatedBy  uint64     `json:"created_by"`                         // 创建者的用户ID
+	CreatedAt  time.Time  `gorm:"type:datetime" json:"created_at"`
+	RevokedAt  *time.Time `gorm:"type:datetime" json:"revoked_at"` // 吊销时间，吊销后不可再使用
+	LastUsedAt *time.Time `gorm:"-" json:"last_used_at"`           // 最后使用时间，存储在Redis中
+}
+
+// TableName 设置APIKey的表名为api_key，如果不设置默认是api_keys
+func (APIKey) TableName() string {
+	return "api_key"
+}
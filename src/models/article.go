@@ -2,14 +2,33 @@ package models
 
 import "time"
 
+// 文章状态
+const (
+	ArticleStatusDraft     = "draft"     // 草稿
+	ArticleStatusScheduled = "scheduled" // 定时发布，到达PublishedAt时自动发布
+	ArticleStatusPublished = "published" // 已发布
+	ArticleStatusArchived  = "archived"  // 已归档
+)
+
 // Article 映射article数据表的结构体
 type Article struct {
-	ID        uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
-	Title     string    `json:"title"`
-	Picture   string    `json:"picture"`
-	Summary   string    `json:"summary"`
-	CreatedAt time.Time `gorm:"type:datetime" json:"created_at"`
-	UpdatedAt time.Time `gorm:"type:datetime" json:"updated_at"`
+	ID              uint64          `gorm:"primaryKey;autoIncrement" json:"id"`
+	Title           string          `json:"title"`
+	Slug            string          `gorm:"type:varchar(191);uniqueIndex" json:"slug"` // 由标题生成的唯一访问标识
+	Picture         string          `json:"picture"`
+	PictureVariants PictureVariants `gorm:"type:json" json:"picture_variants"` // 图片缩略图及宽度版本，用于srcset
+	Summary         string          `json:"summary"`
+	ContentFormat   string          `gorm:"type:varchar(16);default:plain" json:"content_format"`       // 内容格式：plain、markdown、html
+	Tags            []string        `gorm:"-" json:"tags"`                                              // 文章标签，存储在article_tag表
+	CategoryID      uint64          `gorm:"index" json:"category_id"`                                   // 所属分类ID，0表示未分类
+	CategoryPath    []uint64        `gorm:"-" json:"category_path"`                                     // 从顶级分类到所属分类的ID路径，存储在ES中用于按分类查询
+	AuthorID        uint64          `gorm:"index" json:"author_id"`                                     // 作者的用户ID，0表示历史文章没有作者
+	AuthorName      string          `gorm:"-" json:"author_name"`                                       // 作者名称，冗余存储在ES中用于展示
+	ExternalID      *string         `gorm:"type:varchar(191);uniqueIndex" json:"external_id,omitempty"` // 导入文章在原系统中的ID，用于重复导入时去重
+	Status          string          `gorm:"type:varchar(16);default:published;index" json:"status"`     // 文章状态：draft、scheduled、published、archived
+	PublishedAt     *time.Time      `gorm:"type:datetime" json:"published_at"`                          // 发布时间，定时发布时为计划发布时间
+	CreatedAt       time.Time       `gorm:"type:datetime" json:"created_at"`
+	UpdatedAt       time.Time       `gorm:"type:datetime" json:"updated_at"`
 }
 
 // TableName 设置Article的表名为article，如果不设置默认是articles
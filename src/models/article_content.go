@@ -2,8 +2,9 @@ package models
 
 // ArticleContent 映射article_content数据表的结构体
 type ArticleContent struct {
-	ArticleID uint64 `gorm:"primaryKey" json:"article_id"`
-	Content   string `json:"content"`
+	ArticleID   uint64 `gorm:"primaryKey" json:"article_id"`
+	Content     string `json:"content"`      // 文章原始内容
+	ContentHTML string `json:"content_html"` // 渲染并过滤后的HTML内容
 }
 
 // TableName 设置ArticleContent的表名为article_content，如果不设置默认是article_contents
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// ArticleRevision 映射article_revision数据表的结构体，记录文章每次新增和更新后的内容
+type ArticleRevision struct {
+	ID            uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	ArticleID     uint64    `gorm:"uniqueIndex:uk_article_revision" json:"article_id"`
+	Revision      int       `gorm:"uniqueIndex:uk_article_revision" json:"revision"` // 文章内的版本号，从1开始递增
+	Title         string    `json:"title"`
+	Picture       string    `json:"picture"`
+	ContentFormat string    `gorm:"type:varchar(16)" json:"content_format"`
+	Content       string    `json:"content,omitempty"`
+	CreatedAt     time.Time `gorm:"type:datetime" json:"created_at"`
+}
+
+// TableName 设置ArticleRevision的表名为article_revision，如果不设置默认是article_revisions
+func (ArticleRevision) TableName() string {
+	return "article_revision"
+}
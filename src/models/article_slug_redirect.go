@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// ArticleSlugRedirect 映射article_slug_redirect数据表的结构体，记录文章的历史访问标识，用于旧链接跳转
+type ArticleSlugRedirect struct {
+	Slug      string    `gorm:"type:varchar(191);primaryKey" json:"slug"`
+	ArticleID uint64    `gorm:"index" json:"article_id"`
+	CreatedAt time.Time `gorm:"type:datetime" json:"created_at"`
+}
+
+// TableName 设置ArticleSlugRedirect的表名为article_slug_redirect，如果不设置默认是article_slug_redirects
+func (ArticleSlugRedirect) TableName() string {
+	return "article_slug_redirect"
+}
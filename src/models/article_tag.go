@@ -0,0 +1,12 @@
+package models
+
+// ArticleTag 映射article_tag数据表的结构体，记录文章与标签的关联
+type ArticleTag struct {
+	ArticleID uint64 `gorm:"primaryKey" json:"article_id"`
+	TagID     uint64 `gorm:"primaryKey" json:"tag_id"`
+}
+
+// TableName 设置ArticleTag的表名为article_tag，如果不设置默认是article_tags
+func (ArticleTag) TableName() string {
+	return "article_tag"
+}
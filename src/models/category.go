@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// Category 映射category数据表的结构体，ParentID为0表示顶级分类
+type Category struct {
+	ID        uint64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	ParentID  uint64      `gorm:"index" json:"parent_id"`
+	Name      string      `gorm:"type:varchar(64)" json:"name"`
+	CreatedAt time.Time   `gorm:"type:datetime" json:"created_at"`
+	UpdatedAt time.Time   `gorm:"type:datetime" json:"updated_at"`
+	Children  []*Category `gorm:"-" json:"children,omitempty"` // 子分类，仅用于返回分类树
+}
+
+// TableName 设置Category的表名为category，如果不设置默认是categories
+func (Category) TableName() string {
+	return "category"
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// PictureVariants 文章图片的缩略图及不同宽度版本，key为thumb或宽度描述符（如320w），value为图片地址
+type PictureVariants map[string]string
+
+// Value 实现driver.Valuer接口，以JSON格式存储
+func (v PictureVariants) Value() (driver.Value, error) {
+	if v == nil {
+		return nil, nil
+	}
+	return json.Marshal(v)
+}
+
+// Scan 实现sql.Scanner接口，从JSON格式读取
+func (v *PictureVariants) Scan(value interface{}) error {
+	switch data := value.(type) {
+	case nil:
+		*v = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(data, v)
+	case string:
+		return json.Unmarshal([]byte(data), v)
+	default:
+		return fmt.Errorf("unsupported type for PictureVariants: %T", value)
+	}
+}
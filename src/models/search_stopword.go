@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// SearchStopword 映射search_stopword数据表的结构体，检索时忽略的停用词
+type SearchStopword struct {
+	ID        uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	Word      string    `gorm:"type:varchar(64);uniqueIndex" json:"word"`
+	CreatedAt time.Time `gorm:"type:datetime" json:"created_at"`
+}
+
+// TableName 设置SearchStopword的表名为search_stopword，如果不设置默认是search_stopwords
+func (SearchStopword) TableName() string {
+	return "search_stopword"
+}
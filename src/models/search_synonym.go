@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// SearchSynonym 映射search_synonym数据表的结构体，一组互为同义词的检索词
+type SearchSynonym struct {
+	ID        uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	Terms     StringList `gorm:"type:json" json:"terms"`
+	CreatedAt time.Time  `gorm:"type:datetime" json:"created_at"`
+	UpdatedAt time.Time  `gorm:"type:datetime" json:"updated_at"`
+}
+
+// TableName 设置SearchSynonym的表名为search_synonym，如果不设置默认是search_synonyms
+func (SearchSynonym) TableName() string {
+	return "search_synonym"
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// Tag 映射tag数据表的结构体
+type Tag struct {
+	ID        uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name      string    `gorm:"type:varchar(64);uniqueIndex" json:"name"`
+	CreatedAt time.Time `gorm:"type:datetime" json:"created_at"`
+}
+
+// TableName 设置Tag的表名为tag，如果不设置默认是tags
+func (Tag) TableName() string {
+	return "tag"
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// User 映射user数据表的结构体，首次以JWT访问写接口时按subject创建
+type User struct {
+	ID        uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	Subject   string    `gorm:"type:varchar(191);uniqueIndex" json:"subject"` // JWT的sub
+	Name      string    `gorm:"type:varchar(64)" json:"name"`                 // 显示名称，取自JWT的name，没有时使用subject
+	CreatedAt time.Time `gorm:"type:datetime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"type:datetime" json:"updated_at"`
+}
+
+// TableName 设置User的表名为user，如果不设置默认是users
+func (User) TableName() string {
+	return "user"
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"demo/src/dtos"
+	"demo/src/errs"
 	"demo/src/models"
 	"encoding/json"
 	"fmt"
@@ -12,6 +13,7 @@ import (
 	"io"
 	"io/ioutil"
 	"log"
+	"net/http"
 	"os"
 	"strconv"
 	"time"
@@ -19,11 +21,29 @@ import (
 
 const articleIndex = "article"
 
+// maxResultWindow ES默认的index.max_result_window，from+size超过该值时查询会失败
+const maxResultWindow = 10000
+
 type articleUpdate struct {
-	Title     string    `json:"title"`
-	Picture   string    `json:"picture"`
-	Summary   string    `json:"summary"`
-	UpdatedAt time.Time `json:"updated_at"`
+	Title           string                 `json:"title"`
+	Slug            string                 `json:"slug"`
+	Picture         string                 `json:"picture"`
+	PictureVariants models.PictureVariants `json:"picture_variants"`
+	Summary         string                 `json:"summary"`
+	ContentFormat   string                 `json:"content_format"`
+	Tags            []string               `json:"tags"`
+	CategoryID      uint64                 `json:"category_id"`
+	CategoryPath    []uint64               `json:"category_path"`
+	Status          string                 `json:"status"`
+	PublishedAt     *time.Time             `json:"published_at"`
+	UpdatedAt       time.Time              `json:"updated_at"`
+	Content         string                 `json:"content"`
+}
+
+// articleDocument ES中的文章文档，在文章字段之外索引正文纯文本用于全文检索
+type articleDocument struct {
+	*models.Article
+	Content string `json:"content"`
 }
 
 type errorResponse struct {
@@ -33,6 +53,20 @@ type errorResponse struct {
 	} `json:"error"`
 }
 
+// checkResponse 检查ES响应状态并关闭响应体，出错时返回错误原因
+func checkResponse(res *esapi.Response, action string) error {
+	defer res.Body.Close()
+	if !res.IsError() {
+		return nil
+	}
+
+	var e errorResponse
+	if err := json.NewDecoder(res.Body).Decode(&e); err != nil {
+		return fmt.Errorf("error %s! status: %s", action, res.Status())
+	}
+	return fmt.Errorf("error %s! status: %s type: %s reason: %s", action, res.Status(), e.Error.Type, e.Error.Reason)
+}
+
 type ElasticsearchRepository struct {
 	client *elasticsearch.Client
 }
@@ -65,14 +99,17 @@ func InitElasticsearch() *elasticsearch.Client {
 	}
 	defer res.Body.Close()
 
+	// 加载写入后的刷新策略
+	InitRefreshModes()
+
 	fmt.Println("ES connected")
 	return es
 }
 
-// AddArticle 新增ES文章
-func (repo *ElasticsearchRepository) AddArticle(ctx context.Context, article *models.Article) error {
-	// 将models.Article转换为JSON
-	articleJSON, err := json.Marshal(article)
+// AddArticle 新增ES文章，content为用于全文检索的正文纯文本
+func (repo *ElasticsearchRepository) AddArticle(ctx context.Context, article *models.Article, content string) error {
+	// 将文章及正文纯文本转换为JSON
+	articleJSON, err := json.Marshal(articleDocument{Article: article, Content: content})
 	if err != nil {
 		return err
 	}
@@ -85,7 +122,7 @@ func (repo *ElasticsearchRepository) AddArticle(ctx context.Context, article *mo
 		Index:      articleIndex,
 		DocumentID: articleID,
 		Body:       bytes.NewReader(articleJSON),
-		Refresh:    "true", // 设置刷新策略
+		Refresh:    refreshMode(ctx, refreshOpAdd), // 设置刷新策略
 	}
 
 	// 发送请求
@@ -103,9 +140,184 @@ func (repo *ElasticsearchRepository) AddArticle(ctx context.Context, article *mo
 	return nil
 }
 
-// ListArticles 获取ES文章列表
-func (repo *ElasticsearchRepository) ListArticles(ctx context.Context, page, pageSize int, sortField, sortOrder string) (*dtos.ArticleListResponse, error) {
+// publishedFilter 只匹配已发布文章的过滤条件，没有status字段的历史文档视为已发布
+func publishedFilter() map[string]interface{} {
+	return map[string]interface{}{
+		"bool": map[string]interface{}{
+			"should": []interface{}{
+				map[string]interface{}{"term": map[string]interface{}{"status": models.ArticleStatusPublished}},
+				map[string]interface{}{"bool": map[string]interface{}{
+					"must_not": map[string]interface{}{"exists": map[string]interface{}{"field": "status"}},
+				}},
+			},
+			"minimum_should_match": 1,
+		},
+	}
+}
+
+// statusFilter 匹配指定状态的文章，包含已发布时同样匹配没有status字段的历史文档
+func statusFilter(statuses []string) map[string]interface{} {
+	filter := map[string]interface{}{"terms": map[string]interface{}{"status": statuses}}
+	for _, status := range statuses {
+		if status == models.ArticleStatusPublished {
+			return map[string]interface{}{
+				"bool": map[string]interface{}{
+					"should":               []interface{}{filter, publishedFilter()},
+					"minimum_should_match": 1,
+				},
+			}
+		}
+	}
+	return filter
+}
+
+// buildArticleListQuery 根据过滤条件构建ES查询，有关键词时检索标题、摘要和正文
+func buildArticleListQuery(filter *dtos.ArticleFilter) map[string]interface{} {
+	boolQuery := map[string]interface{}{
+		"filter": buildArticleListFilters(filter),
+	}
+	if filter.Q != "" {
+		boolQuery["must"] = map[string]interface{}{
+			"multi_match": map[string]interface{}{
+				"query":  filter.Q,
+				"fields": []string{"title^3", "summary^2", "content"},
+			},
+		}
+	}
+	return map[string]interface{}{"bool": boolQuery}
+}
+
+// buildArticleListFilters 根据过滤条件构建ES过滤条件
+func buildArticleListFilters(req *dtos.ArticleFilter) []interface{} {
+	// 未指定状态时只返回已发布的文章，按状态过滤的权限由服务层检查
+	filters := []interface{}{publishedFilter()}
+	if len(req.Status) > 0 {
+		filters = []interface{}{statusFilter(req.Status)}
+	}
+
+	// 标签过滤：any匹配任一标签，all匹配全部标签
+	if len(req.Tags) > 0 {
+		if req.TagsMode == "all" {
+			for _, tag := range req.Tags {
+				filters = append(filters, map[string]interface{}{
+					"term": map[string]interface{}{"tags": tag},
+				})
+			}
+		} else {
+			filters = append(filters, map[string]interface{}{
+				"terms": map[string]interface{}{"tags": req.Tags},
+			})
+		}
+	}
+
+	// 分类过滤：category_path包含该分类即属于该分类或其子分类
+	if req.CategoryID > 0 {
+		filters = append(filters, map[string]interface{}{
+			"term": map[string]interface{}{"category_path": req.CategoryID},
+		})
+	}
+
+	// 作者过滤
+	if req.AuthorID > 0 {
+		filters = append(filters, map[string]interface{}{
+			"term": map[string]interface{}{"author_id": req.AuthorID},
+		})
+	}
+
+	// 时间范围过滤
+	if r := dateRange(req.CreatedFrom, req.CreatedTo); r != nil {
+		filters = append(filters, map[string]interface{}{
+			"range": map[string]interface{}{"created_at": r},
+		})
+	}
+	if r := dateRange(req.UpdatedFrom, req.UpdatedTo); r != nil {
+		filters = append(filters, map[string]interface{}{
+			"range": map[string]interface{}{"updated_at": r},
+		})
+	}
+
+	// 封面图过滤
+	if req.HasPicture != nil {
+		if *req.HasPicture {
+			filters = append(filters, hasPictureFilter())
+		} else {
+			filters = append(filters, noPictureFilter())
+		}
+	}
+
+	return filters
+}
+
+// noPictureFilter 匹配没有封面图的文章，没有封面图时picture为空字符串
+func noPictureFilter() map[string]interface{} {
+	return map[string]interface{}{
+		"bool": map[string]interface{}{
+			"should": []interface{}{
+				map[string]interface{}{"term": map[string]interface{}{"picture.keyword": ""}},
+				map[string]interface{}{"bool": map[string]interface{}{
+					"must_not": map[string]interface{}{"exists": map[string]interface{}{"field": "picture"}},
+				}},
+			},
+			"minimum_should_match": 1,
+		},
+	}
+}
+
+// hasPictureFilter 匹配有封面图的文章
+func hasPictureFilter() map[string]interface{} {
+	return map[string]interface{}{
+		"bool": map[string]interface{}{"must_not": noPictureFilter()},
+	}
+}
+
+// dateRange 构建时间范围条件，两端都未设置时返回nil
+func dateRange(from, to time.Time) map[string]interface{} {
+	if from.IsZero() && to.IsZero() {
+		return nil
+	}
+	r := map[string]interface{}{}
+	if !from.IsZero() {
+		r["gte"] = from.Format(time.RFC3339Nano)
+	}
+	if !to.IsZero() {
+		r["lte"] = to.Format(time.RFC3339Nano)
+	}
+	return r
+}
+
+// articleSortFields 列表支持的排序字段到ES字段的映射，title使用keyword子字段排序
+var articleSortFields = map[string]string{
+	"id":         "id",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+	"title":      "title.keyword",
+}
+
+// articleListFields 列表fields参数支持的字段
+var articleListFields = map[string]bool{
+	"id": true, "title": true, "slug": true, "picture": true, "picture_variants": true,
+	"summary": true, "content_format": true, "tags": true, "category_id": true,
+	"category_path": true, "author_id": true, "author_name": true, "status": true, "published_at": true, "created_at": true, "updated_at": true,
+}
+
+// articleSort 构建排序条件，以id作为第二排序字段，保证排序值相同时顺序稳定
+func articleSort(sortField, order string) []interface{} {
+	return []interface{}{
+		map[string]interface{}{
+			sortField: map[string]interface{}{"order": order},
+		},
+		map[string]interface{}{
+			"id": map[string]interface{}{"order": order},
+		},
+	}
+}
+
+// ListArticles 获取ES文章列表，支持页码分页和基于search_after的游标分页
+func (repo *ElasticsearchRepository) ListArticles(ctx context.Context, req *dtos.ArticleListRequest) (*dtos.ArticleListResponse, error) {
+	page, pageSize := req.Page, req.PageSize
+
 	// 设置默认排序字段和排序顺序
+	sortField, sortOrder := articleSortFields[req.Sort], req.Order
 	if sortField == "" {
 		sortField = "created_at"
 	}
@@ -113,20 +325,58 @@ func (repo *ElasticsearchRepository) ListArticles(ctx context.Context, page, pag
 		sortOrder = "desc"
 	}
 
-	// 计算要跳过的文档数量
-	from := (page - 1) * pageSize
-
 	// 构建查询
 	query := map[string]interface{}{
-		"from": from,
-		"size": pageSize,
-		"sort": []interface{}{
-			map[string]interface{}{
-				sortField: map[string]interface{}{"order": sortOrder},
-			},
-		},
+		"size":  pageSize,
+		"query": buildArticleListQuery(&req.ArticleFilter),
+	}
+
+	// 只返回指定字段
+	if len(req.Fields) > 0 {
+		for _, field := range req.Fields {
+			if !articleListFields[field] {
+				return nil, fmt.Errorf("%w: %s", errs.ErrInvalidListField, field)
+			}
+		}
+		query["_source"] = map[string]interface{}{"includes": req.Fields}
+	} else {
+		// 正文只用于检索，列表不返回
+		query["_source"] = map[string]interface{}{"excludes": []string{"content"}}
+	}
+
+	// 分面统计
+	if len(req.Facets) > 0 {
+		aggs, err := buildFacetAggs(req.Facets)
+		if err != nil {
+			return nil, err
+		}
+		query["aggs"] = aggs
+	}
+
+	// 游标分页时按游标方向设置search_after，向前翻页时反转排序，取回结果后再反转
+	var cursor *listCursor
+	queryOrder := sortOrder
+	if req.Cursor != "" {
+		var err error
+		if cursor, err = decodeCursor(req.Cursor, sortField, sortOrder); err != nil {
+			return nil, err
+		}
+		if cursor.Direction == cursorPrev {
+			queryOrder = reverseOrder(sortOrder)
+		}
+		query["search_after"] = cursor.Values
+		page = 0 // 游标分页没有页码
+	} else {
+		// 计算要跳过的文档数量
+		from := (page - 1) * pageSize
+		if from+pageSize > maxResultWindow {
+			return nil, errs.ErrPageTooDeep
+		}
+		query["from"] = from
 	}
 
+	query["sort"] = articleSort(sortField, queryOrder)
+
 	var buf bytes.Buffer
 	if err := json.NewEncoder(&buf).Encode(query); err != nil {
 		return nil, err
@@ -135,7 +385,7 @@ func (repo *ElasticsearchRepository) ListArticles(ctx context.Context, page, pag
 	// 执行查询
 	res, err := repo.client.Search(
 		repo.client.Search.WithContext(ctx),
-		repo.client.Search.WithIndex("article"),
+		repo.client.Search.WithIndex(articleIndex),
 		repo.client.Search.WithBody(&buf),
 		repo.client.Search.WithTrackTotalHits(true),
 	)
@@ -159,21 +409,32 @@ func (repo *ElasticsearchRepository) ListArticles(ctx context.Context, page, pag
 		return nil, fmt.Errorf("article query returns error! status: %s type: %s reason: %s", res.Status(), e.Error.Type, e.Error.Reason)
 	}
 
-	// 解析结果
+	// 解析结果，排序值使用json.Number保留精度
 	var esResponse struct {
 		Hits struct {
 			Total struct {
 				Value int64 `json:"value"`
 			} `json:"total"`
 			Hits []struct {
-				Source models.Article `json:"_source"`
+				Source json.RawMessage `json:"_source"`
+				Sort   []interface{}   `json:"sort"`
 			} `json:"hits"`
 		} `json:"hits"`
+		Aggregations map[string]json.RawMessage `json:"aggregations"`
 	}
-	if err := json.Unmarshal(responseBytes, &esResponse); err != nil {
+	decoder := json.NewDecoder(bytes.NewReader(responseBytes))
+	decoder.UseNumber()
+	if err := decoder.Decode(&esResponse); err != nil {
 		return nil, fmt.Errorf("error parsing the response body: %s", err)
 	}
 
+	hits := esResponse.Hits.Hits
+	if cursor != nil && cursor.Direction == cursorPrev {
+		for i, j := 0, len(hits)-1; i < j; i, j = i+1, j-1 {
+			hits[i], hits[j] = hits[j], hits[i]
+		}
+	}
+
 	// 计算总页数
 	totalPages := int(esResponse.Hits.Total.Value) / pageSize
 	if int(esResponse.Hits.Total.Value)%pageSize > 0 {
@@ -181,9 +442,22 @@ func (repo *ElasticsearchRepository) ListArticles(ctx context.Context, page, pag
 	}
 
 	// 构建文章列表
-	articles := make([]models.Article, len(esResponse.Hits.Hits))
-	for i, hit := range esResponse.Hits.Hits {
-		articles[i] = hit.Source
+	// 指定fields时直接返回ES中的部分文档，否则解析为完整的文章
+	var list interface{}
+	if len(req.Fields) > 0 {
+		sources := make([]json.RawMessage, len(hits))
+		for i, hit := range hits {
+			sources[i] = hit.Source
+		}
+		list = sources
+	} else {
+		articles := make([]models.Article, len(hits))
+		for i, hit := range hits {
+			if err := json.Unmarshal(hit.Source, &articles[i]); err != nil {
+				return nil, fmt.Errorf("error parsing the response body: %s", err)
+			}
+		}
+		list = articles
 	}
 
 	// 构建响应数据
@@ -194,7 +468,28 @@ func (repo *ElasticsearchRepository) ListArticles(ctx context.Context, page, pag
 			PageSize:  pageSize,
 			TotalPage: totalPages,
 		},
-		List: articles,
+		List: list,
+	}
+
+	// 解析分面统计结果
+	if len(req.Facets) > 0 {
+		if data.Facets, err = parseFacets(esResponse.Aggregations); err != nil {
+			return nil, err
+		}
+	}
+
+	// 生成前后翻页游标：本页取满或是向前翻页得到的结果时后面还有数据，
+	// 本页不是第一页时前面还有数据
+	if len(hits) > 0 {
+		full := len(hits) == pageSize
+		hasNext := full || (cursor != nil && cursor.Direction == cursorPrev)
+		hasPrev := (cursor == nil && page > 1) || (cursor != nil && (cursor.Direction == cursorNext || full))
+		if hasNext {
+			data.PageData.NextCursor = encodeCursor(listCursor{Values: hits[len(hits)-1].Sort, Direction: cursorNext, Sort: sortField, Order: sortOrder})
+		}
+		if hasPrev {
+			data.PageData.PrevCursor = encodeCursor(listCursor{Values: hits[0].Sort, Direction: cursorPrev, Sort: sortField, Order: sortOrder})
+		}
 	}
 
 	response := &dtos.ArticleListResponse{
@@ -205,18 +500,225 @@ func (repo *ElasticsearchRepository) ListArticles(ctx context.Context, page, pag
 	return response, nil
 }
 
-// UpdateArticle 更新ES文章
-func (repo *ElasticsearchRepository) UpdateArticle(ctx context.Context, article *models.Article) error {
+// CountTags 通过terms聚合统计标签使用次数，按次数倒序返回前size个标签
+func (repo *ElasticsearchRepository) CountTags(ctx context.Context, size int) ([]dtos.TagCount, error) {
+	query := map[string]interface{}{
+		"size":  0,
+		"query": publishedFilter(), // 只统计已发布的文章
+		"aggs": map[string]interface{}{
+			"tags": map[string]interface{}{
+				"terms": map[string]interface{}{"field": "tags", "size": size},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(query); err != nil {
+		return nil, err
+	}
+
+	res, err := repo.client.Search(
+		repo.client.Search.WithContext(ctx),
+		repo.client.Search.WithIndex(articleIndex),
+		repo.client.Search.WithBody(&buf),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return nil, checkResponse(res, "counting tags")
+	}
+
+	// 解析聚合结果
+	var esResponse struct {
+		Aggregations struct {
+			Tags struct {
+				Buckets []struct {
+					Key      string `json:"key"`
+					DocCount int64  `json:"doc_count"`
+				} `json:"buckets"`
+			} `json:"tags"`
+		} `json:"aggregations"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&esResponse); err != nil {
+		return nil, fmt.Errorf("error parsing the response body: %s", err)
+	}
+
+	tags := make([]dtos.TagCount, len(esResponse.Aggregations.Tags.Buckets))
+	for i, bucket := range esResponse.Aggregations.Tags.Buckets {
+		tags[i] = dtos.TagCount{Name: bucket.Key, Count: bucket.DocCount}
+	}
+
+	return tags, nil
+}
+
+// SuggestArticles 根据标题前缀联想已发布的文章，只返回id、title和slug
+func (repo *ElasticsearchRepository) SuggestArticles(ctx context.Context, prefix string, size int) ([]dtos.ArticleSuggestion, error) {
+	query := map[string]interface{}{
+		"size":    size,
+		"_source": []string{"id", "title", "slug"},
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"must": map[string]interface{}{
+					"multi_match": map[string]interface{}{
+						"query": prefix,
+						"type":  "bool_prefix",
+						"fields": []string{
+							"title.suggest",
+							"title.suggest._2gram",
+							"title.suggest._3gram",
+						},
+					},
+				},
+				"filter": publishedFilter(),
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(query); err != nil {
+		return nil, err
+	}
+
+	// 联想请求对延迟敏感，不统计总数并精简返回内容
+	res, err := repo.client.Search(
+		repo.client.Search.WithContext(ctx),
+		repo.client.Search.WithIndex(articleIndex),
+		repo.client.Search.WithBody(&buf),
+		repo.client.Search.WithTrackTotalHits(false),
+		repo.client.Search.WithFilterPath("hits.hits._source"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return nil, checkResponse(res, "suggesting articles")
+	}
+
+	var esResponse struct {
+		Hits struct {
+			Hits []struct {
+				Source dtos.ArticleSuggestion `json:"_source"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&esResponse); err != nil {
+		return nil, fmt.Errorf("error parsing the response body: %s", err)
+	}
+
+	suggestions := make([]dtos.ArticleSuggestion, len(esResponse.Hits.Hits))
+	for i, hit := range esResponse.Hits.Hits {
+		suggestions[i] = hit.Source
+	}
+
+	return suggestions, nil
+}
+
+// RelatedArticles 使用more_like_this查询与指定文章内容相似的已发布文章，有相同标签的文章排序更靠前
+func (repo *ElasticsearchRepository) RelatedArticles(ctx context.Context, articleID uint64, tags []string, size int) ([]dtos.RelatedArticle, error) {
+	should := []interface{}{
+		map[string]interface{}{
+			"more_like_this": map[string]interface{}{
+				"fields": []string{"title", "summary", "content"},
+				"like": []interface{}{
+					map[string]interface{}{"_index": articleIndex, "_id": strconv.FormatUint(articleID, 10)},
+				},
+				"min_term_freq":   1,
+				"min_doc_freq":    1,
+				"max_query_terms": 25,
+			},
+		},
+	}
+	if len(tags) > 0 {
+		should = append(should, map[string]interface{}{
+			"terms": map[string]interface{}{"tags": tags, "boost": 2},
+		})
+	}
+
+	query := map[string]interface{}{
+		"size":    size,
+		"_source": []string{"id", "title", "slug", "picture", "summary", "published_at"},
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"should":               should,
+				"minimum_should_match": 1,
+				"filter":               publishedFilter(),
+				"must_not": map[string]interface{}{
+					"ids": map[string]interface{}{"values": []string{strconv.FormatUint(articleID, 10)}},
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(query); err != nil {
+		return nil, err
+	}
+
+	res, err := repo.client.Search(
+		repo.client.Search.WithContext(ctx),
+		repo.client.Search.WithIndex(articleIndex),
+		repo.client.Search.WithBody(&buf),
+		repo.client.Search.WithTrackTotalHits(false),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return nil, checkResponse(res, "querying related articles")
+	}
+
+	var esResponse struct {
+		Hits struct {
+			Hits []struct {
+				Source dtos.RelatedArticle `json:"_source"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&esResponse); err != nil {
+		return nil, fmt.Errorf("error parsing the response body: %s", err)
+	}
+
+	articles := make([]dtos.RelatedArticle, len(esResponse.Hits.Hits))
+	for i, hit := range esResponse.Hits.Hits {
+		articles[i] = hit.Source
+	}
+
+	return articles, nil
+}
+
+// newArticleUpdate 构建更新ES文章时写入的字段
+func newArticleUpdate(article *models.Article, content string) articleUpdate {
+	return articleUpdate{
+		Title:           article.Title,
+		Slug:            article.Slug,
+		Picture:         article.Picture,
+		PictureVariants: article.PictureVariants,
+		Summary:         article.Summary,
+		ContentFormat:   article.ContentFormat,
+		Tags:            article.Tags,
+		CategoryID:      article.CategoryID,
+		CategoryPath:    article.CategoryPath,
+		Status:          article.Status,
+		PublishedAt:     article.PublishedAt,
+		UpdatedAt:       article.UpdatedAt,
+		Content:         content,
+	}
+}
+
+// UpdateArticle 更新ES文章，content为用于全文检索的正文纯文本
+func (repo *ElasticsearchRepository) UpdateArticle(ctx context.Context, article *models.Article, content string) error {
 	// 构建ES文章更新数据
 	update := struct {
 		Doc articleUpdate `json:"doc"`
 	}{
-		Doc: articleUpdate{
-			Title:     article.Title,
-			Picture:   article.Picture,
-			Summary:   article.Summary,
-			UpdatedAt: article.UpdatedAt,
-		},
+		Doc: newArticleUpdate(article, content),
 	}
 	articleJSON, err := json.Marshal(update)
 	if err != nil {
@@ -231,7 +733,7 @@ func (repo *ElasticsearchRepository) UpdateArticle(ctx context.Context, article
 		Index:      articleIndex,
 		DocumentID: articleID,
 		Body:       bytes.NewReader(articleJSON),
-		Refresh:    "true",
+		Refresh:    refreshMode(ctx, refreshOpUpdate),
 	}
 
 	// 发送请求
@@ -252,3 +754,104 @@ func (repo *ElasticsearchRepository) UpdateArticle(ctx context.Context, article
 
 	return nil
 }
+
+// DeleteArticle 删除ES文章，文档不存在时忽略
+func (repo *ElasticsearchRepository) DeleteArticle(ctx context.Context, articleID uint64) error {
+	res, err := repo.client.Delete(
+		articleIndex,
+		strconv.FormatUint(articleID, 10),
+		repo.client.Delete.WithContext(ctx),
+		repo.client.Delete.WithRefresh(refreshMode(ctx, refreshOpDelete)),
+	)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode == http.StatusNotFound {
+		res.Body.Close()
+		return nil
+	}
+	return checkResponse(res, fmt.Sprintf("deleting article ID=%d", articleID))
+}
+
+// UpdateArticleStatus 更新ES文章状态和发布时间
+func (repo *ElasticsearchRepository) UpdateArticleStatus(ctx context.Context, articleID uint64, status string, publishedAt *time.Time) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"doc": map[string]interface{}{
+			"status":       status,
+			"published_at": publishedAt,
+			"updated_at":   time.Now(),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	req := esapi.UpdateRequest{
+		Index:      articleIndex,
+		DocumentID: strconv.FormatUint(articleID, 10),
+		Body:       bytes.NewReader(body),
+		Refresh:    refreshMode(ctx, refreshOpUpdate),
+	}
+
+	res, err := req.Do(ctx, repo.client)
+	if err != nil {
+		return err
+	}
+	return checkResponse(res, fmt.Sprintf("updating status of article ID=%d", articleID))
+}
+
+// UpdateAuthorName 更新作者全部文章中冗余的作者名称
+func (repo *ElasticsearchRepository) UpdateAuthorName(ctx context.Context, authorID uint64, name string) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"query": map[string]interface{}{
+			"term": map[string]interface{}{"author_id": authorID},
+		},
+		"script": map[string]interface{}{
+			"source": "ctx._source.author_name = params.name",
+			"params": map[string]interface{}{"name": name},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	res, err := repo.client.UpdateByQuery(
+		[]string{articleIndex},
+		repo.client.UpdateByQuery.WithContext(ctx),
+		repo.client.UpdateByQuery.WithBody(bytes.NewReader(body)),
+		repo.client.UpdateByQuery.WithConflicts("proceed"),
+		repo.client.UpdateByQuery.WithRefresh(refreshMode(ctx, refreshOpByQuery) != RefreshFalse),
+	)
+	if err != nil {
+		return err
+	}
+	return checkResponse(res, fmt.Sprintf("updating author name of user ID=%d", authorID))
+}
+
+// UpdateCategoryPath 更新分类下所有文章的分类路径，用于分类移动后同步ES
+func (repo *ElasticsearchRepository) UpdateCategoryPath(ctx context.Context, categoryID uint64, categoryPath []uint64) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"query": map[string]interface{}{
+			"term": map[string]interface{}{"category_id": categoryID},
+		},
+		"script": map[string]interface{}{
+			"source": "ctx._source.category_path = params.path",
+			"params": map[string]interface{}{"path": categoryPath},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	res, err := repo.client.UpdateByQuery(
+		[]string{articleIndex},
+		repo.client.UpdateByQuery.WithContext(ctx),
+		repo.client.UpdateByQuery.WithBody(bytes.NewReader(body)),
+		repo.client.UpdateByQuery.WithConflicts("proceed"),
+		repo.client.UpdateByQuery.WithRefresh(refreshMode(ctx, refreshOpByQuery) != RefreshFalse),
+	)
+	if err != nil {
+		return err
+	}
+	return checkResponse(res, fmt.Sprintf("updating category path of category ID=%d", categoryID))
+}
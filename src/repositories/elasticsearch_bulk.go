@@ -0,0 +1,106 @@
+package repositories
+
+import (
+	"bytes"
+	"context"
+	"demo/src/models"
+	"encoding/json"
+	"fmt"
+	"github.com/elastic/go-elasticsearch/v8/esapi"
+	"strconv"
+)
+
+// ArticleBulkDocument 批量写入ES的一篇文章
+type ArticleBulkDocument struct {
+	Article *models.Article
+	Content string // 用于全文检索的正文纯文本
+	Created bool   // 新增的文章写入完整文档，否则只更新文章字段
+}
+
+type bulkResponse struct {
+	Errors bool `json:"errors"`
+	Items  []map[string]struct {
+		Status int `json:"status"`
+		Error  *struct {
+			Type   string `json:"type"`
+			Reason string `json:"reason"`
+		} `json:"error"`
+	} `json:"items"`
+}
+
+// BulkSaveArticles 使用_bulk接口批量写入ES文章，不刷新索引，需要在全部写入后调用RefreshArticles刷新一次；
+// 单篇失败时记录在返回的对应位置，请求本身失败时返回err
+func (repo *ElasticsearchRepository) BulkSaveArticles(ctx context.Context, docs []ArticleBulkDocument) ([]error, error) {
+	return repo.bulkSaveArticles(ctx, articleIndex, docs)
+}
+
+// bulkSaveArticles 将文章批量写入指定的索引或别名
+func (repo *ElasticsearchRepository) bulkSaveArticles(ctx context.Context, index string, docs []ArticleBulkDocument) ([]error, error) {
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	for _, doc := range docs {
+		meta := map[string]interface{}{
+			"_index": index,
+			"_id":    strconv.FormatUint(doc.Article.ID, 10),
+		}
+
+		var action string
+		var source interface{}
+		if doc.Created {
+			action, source = "index", articleDocument{Article: doc.Article, Content: doc.Content}
+		} else {
+			action, source = "update", map[string]interface{}{"doc": newArticleUpdate(doc.Article, doc.Content)}
+		}
+
+		if err := encoder.Encode(map[string]interface{}{action: meta}); err != nil {
+			return nil, err
+		}
+		if err := encoder.Encode(source); err != nil {
+			return nil, err
+		}
+	}
+
+	res, err := esapi.BulkRequest{Body: &body}.Do(ctx, repo.client)
+	if err != nil {
+		return nil, err
+	}
+	if res.IsError() {
+		return nil, checkResponse(res, "bulk indexing articles")
+	}
+	defer res.Body.Close()
+
+	var result bulkResponse
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	if len(result.Items) != len(docs) {
+		return nil, fmt.Errorf("error bulk indexing articles! expected %d results, got %d", len(docs), len(result.Items))
+	}
+
+	itemErrs := make([]error, len(docs))
+	for i, item := range result.Items {
+		for _, status := range item {
+			if status.Error != nil {
+				itemErrs[i] = fmt.Errorf("error indexing article ID=%d! status: %d type: %s reason: %s", docs[i].Article.ID, status.Status, status.Error.Type, status.Error.Reason)
+			}
+		}
+	}
+	return itemErrs, nil
+}
+
+// RefreshArticles 批量写入后刷新文章索引，使写入的文章可以被搜索；刷新策略为false时不刷新，
+// wait_for没有对应的刷新接口，与true一样刷新一次
+func (repo *ElasticsearchRepository) RefreshArticles(ctx context.Context) error {
+	if refreshMode(ctx, refreshOpBulk) == RefreshFalse {
+		return nil
+	}
+
+	res, err := repo.client.Indices.Refresh(
+		repo.client.Indices.Refresh.WithContext(ctx),
+		repo.client.Indices.Refresh.WithIndex(articleIndex),
+	)
+	if err != nil {
+		return err
+	}
+	return checkResponse(res, "refreshing article index")
+}
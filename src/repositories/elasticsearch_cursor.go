@@ -0,0 +1,58 @@
+package repositories
+
+import (
+	"bytes"
+	"demo/src/errs"
+	"encoding/base64"
+	"encoding/json"
+)
+
+// 游标翻页方向
+const (
+	cursorNext = "next"
+	cursorPrev = "prev"
+)
+
+// listCursor 列表分页游标，记录边界文档的排序值（search_after）及生成游标时的排序方式
+type listCursor struct {
+	Values    []interface{} `json:"v"`
+	Direction string        `json:"d"`
+	Sort      string        `json:"s"`
+	Order     string        `json:"o"`
+}
+
+// encodeCursor 将游标编码为不透明的字符串
+func encodeCursor(cursor listCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor 解析游标，排序方式与当前请求不一致时返回错误
+func decodeCursor(value, sortField, sortOrder string) (*listCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, errs.ErrInvalidCursor
+	}
+
+	// 使用json.Number保留排序值的精度
+	var cursor listCursor
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&cursor); err != nil {
+		return nil, errs.ErrInvalidCursor
+	}
+
+	if len(cursor.Values) == 0 || cursor.Sort != sortField || cursor.Order != sortOrder ||
+		(cursor.Direction != cursorNext && cursor.Direction != cursorPrev) {
+		return nil, errs.ErrInvalidCursor
+	}
+	return &cursor, nil
+}
+
+// reverseOrder 反转排序方向，用于向前翻页
+func reverseOrder(order string) string {
+	if order == "asc" {
+		return "desc"
+	}
+	return "asc"
+}
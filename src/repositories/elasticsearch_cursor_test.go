@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"demo/src/errs"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDecodeCursor(t *testing.T) {
+	encode := func(raw string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(raw))
+	}
+
+	tests := []struct {
+		name    string
+		value   string
+		sort    string
+		order   string
+		want    *listCursor
+		wantErr error
+	}{
+		{
+			name:  "next page",
+			value: encodeCursor(listCursor{Values: []interface{}{json.Number("1700000000000"), json.Number("42")}, Direction: cursorNext, Sort: "created_at", Order: "desc"}),
+			sort:  "created_at",
+			order: "desc",
+			want:  &listCursor{Values: []interface{}{json.Number("1700000000000"), json.Number("42")}, Direction: cursorNext, Sort: "created_at", Order: "desc"},
+		},
+		{
+			name:  "previous page with string value",
+			value: encodeCursor(listCursor{Values: []interface{}{"hello", json.Number("7")}, Direction: cursorPrev, Sort: "title", Order: "asc"}),
+			sort:  "title",
+			order: "asc",
+			want:  &listCursor{Values: []interface{}{"hello", json.Number("7")}, Direction: cursorPrev, Sort: "title", Order: "asc"},
+		},
+		{name: "bad base64", value: "not base64!", sort: "id", order: "desc", wantErr: errs.ErrInvalidCursor},
+		{name: "padded base64", value: base64.URLEncoding.EncodeToString([]byte(`{"v":[1],"d":"next","s":"id","o":"desc"}`)), sort: "id", order: "desc", wantErr: errs.ErrInvalidCursor},
+		{name: "bad json", value: encode(`{"v":[1],`), sort: "id", order: "desc", wantErr: errs.ErrInvalidCursor},
+		{name: "wrong json type", value: encode(`{"v":"1","d":"next","s":"id","o":"desc"}`), sort: "id", order: "desc", wantErr: errs.ErrInvalidCursor},
+		{name: "no values", value: encode(`{"v":[],"d":"next","s":"id","o":"desc"}`), sort: "id", order: "desc", wantErr: errs.ErrInvalidCursor},
+		{name: "sort mismatch", value: encode(`{"v":[1],"d":"next","s":"title","o":"desc"}`), sort: "id", order: "desc", wantErr: errs.ErrInvalidCursor},
+		{name: "order mismatch", value: encode(`{"v":[1],"d":"next","s":"id","o":"asc"}`), sort: "id", order: "desc", wantErr: errs.ErrInvalidCursor},
+		{name: "invalid direction", value: encode(`{"v":[1],"d":"sideways","s":"id","o":"desc"}`), sort: "id", order: "desc", wantErr: errs.ErrInvalidCursor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cursor, err := decodeCursor(tt.value, tt.sort, tt.order)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("decodeCursor() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decodeCursor() error = %v", err)
+			}
+			if !reflect.DeepEqual(cursor, tt.want) {
+				t.Errorf("decodeCursor() = %+v, want %+v", cursor, tt.want)
+			}
+		})
+	}
+}
+
+// 超过2^53的排序值（如long类型的ID）经过编码和解析后不能丢失精度
+func TestCursorRoundTripLargeNumber(t *testing.T) {
+	large := json.Number("9007199254740993") // 2^53 + 1，转换为float64时变为9007199254740992
+	value := encodeCursor(listCursor{Values: []interface{}{large}, Direction: cursorNext, Sort: "id", Order: "asc"})
+
+	cursor, err := decodeCursor(value, "id", "asc")
+	if err != nil {
+		t.Fatalf("decodeCursor() error = %v", err)
+	}
+	if got := cursor.Values[0]; got != large {
+		t.Fatalf("decodeCursor() value = %#v, want %#v", got, large)
+	}
+	if again := encodeCursor(*cursor); again != value {
+		t.Errorf("encodeCursor() after decode = %q, want %q", again, value)
+	}
+}
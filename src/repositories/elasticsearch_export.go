@@ -0,0 +1,149 @@
+package repositories
+
+import (
+	"bytes"
+	"context"
+	"demo/src/dtos"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+)
+
+// scanKeepAlive 逐批获取时时间点（PIT）的保留时间，每批请求都会续期，需要大于处理一批的时间
+const scanKeepAlive = "5m"
+
+// ScanArticleIDs 按过滤条件和排序使用search_after逐批获取文章ID，每批调用一次fn，fn返回错误时停止；
+// 在同一个时间点（PIT）上分页，扫描期间新增、修改或删除的文章不会导致重复或遗漏
+func (repo *ElasticsearchRepository) ScanArticleIDs(ctx context.Context, filter *dtos.ArticleFilter, sort, order string, batchSize int, fn func(ids []uint64) error) error {
+	sortField := articleSortFields[sort]
+	if sortField == "" {
+		sortField = "created_at"
+	}
+	if order == "" {
+		order = "desc"
+	}
+
+	pitID, err := repo.openPointInTime(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// 请求被取消时也需要释放PIT
+		if err := repo.closePointInTime(context.WithoutCancel(ctx), pitID); err != nil {
+			log.Printf("Failed to close point in time: %v", err)
+		}
+	}()
+
+	var searchAfter []interface{}
+	for {
+		query := map[string]interface{}{
+			"size":             batchSize,
+			"_source":          false,
+			"track_total_hits": false,
+			"query":            buildArticleListQuery(filter),
+			"sort":             articleSort(sortField, order),
+			"pit":              map[string]interface{}{"id": pitID, "keep_alive": scanKeepAlive},
+		}
+		if searchAfter != nil {
+			query["search_after"] = searchAfter
+		}
+
+		var buf bytes.Buffer
+		if err := json.NewEncoder(&buf).Encode(query); err != nil {
+			return err
+		}
+
+		// 使用PIT时不能指定索引
+		res, err := repo.client.Search(
+			repo.client.Search.WithContext(ctx),
+			repo.client.Search.WithBody(&buf),
+		)
+		if err != nil {
+			return err
+		}
+		if res.IsError() {
+			return checkResponse(res, "scanning articles")
+		}
+
+		// 排序值使用json.Number保留精度
+		var esResponse struct {
+			PitID string `json:"pit_id"`
+			Hits  struct {
+				Hits []struct {
+					ID   string        `json:"_id"`
+					Sort []interface{} `json:"sort"`
+				} `json:"hits"`
+			} `json:"hits"`
+		}
+		decoder := json.NewDecoder(res.Body)
+		decoder.UseNumber()
+		err = decoder.Decode(&esResponse)
+		res.Body.Close()
+		if err != nil {
+			return fmt.Errorf("error parsing the response body: %s", err)
+		}
+
+		// 每次响应可能返回新的PIT ID，后续请求需要使用最新的
+		if esResponse.PitID != "" {
+			pitID = esResponse.PitID
+		}
+		hits := esResponse.Hits.Hits
+		if len(hits) == 0 {
+			return nil
+		}
+
+		ids := make([]uint64, len(hits))
+		for i, hit := range hits {
+			if ids[i], err = strconv.ParseUint(hit.ID, 10, 64); err != nil {
+				return fmt.Errorf("invalid article ID %q in index: %w", hit.ID, err)
+			}
+		}
+		if err := fn(ids); err != nil {
+			return err
+		}
+
+		if len(hits) < batchSize {
+			return nil
+		}
+		searchAfter = hits[len(hits)-1].Sort
+	}
+}
+
+// openPointInTime 在文章索引上打开一个时间点，返回PIT ID
+func (repo *ElasticsearchRepository) openPointInTime(ctx context.Context) (string, error) {
+	res, err := repo.client.OpenPointInTime([]string{articleIndex}, scanKeepAlive,
+		repo.client.OpenPointInTime.WithContext(ctx),
+	)
+	if err != nil {
+		return "", err
+	}
+	if res.IsError() {
+		return "", checkResponse(res, "opening point in time")
+	}
+	defer res.Body.Close()
+
+	var pit struct {
+		ID string `json:"id"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&pit); err != nil {
+		return "", fmt.Errorf("error parsing the response body: %s", err)
+	}
+	return pit.ID, nil
+}
+
+// closePointInTime 释放时间点占用的资源
+func (repo *ElasticsearchRepository) closePointInTime(ctx context.Context, pitID string) error {
+	body, err := json.Marshal(map[string]interface{}{"id": pitID})
+	if err != nil {
+		return err
+	}
+	res, err := repo.client.ClosePointInTime(
+		repo.client.ClosePointInTime.WithContext(ctx),
+		repo.client.ClosePointInTime.WithBody(bytes.NewReader(body)),
+	)
+	if err != nil {
+		return err
+	}
+	return checkResponse(res, "closing point in time")
+}
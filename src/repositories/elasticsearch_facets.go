@@ -0,0 +1,140 @@
+package repositories
+
+import (
+	"bytes"
+	"demo/src/dtos"
+	"demo/src/errs"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// 列表支持的分面
+const (
+	facetTags       = "tags"
+	facetCategory   = "category"
+	facetMonth      = "month"
+	facetHasPicture = "has_picture"
+)
+
+const facetSize = 50 // 标签和分类分面最多返回的数量
+
+// buildFacetAggs 根据请求的分面构建聚合，分面统计与列表使用相同的过滤条件
+func buildFacetAggs(facets []string) (map[string]interface{}, error) {
+	aggs := map[string]interface{}{}
+	for _, facet := range facets {
+		switch facet {
+		case facetTags:
+			aggs[facet] = map[string]interface{}{
+				"terms": map[string]interface{}{"field": "tags", "size": facetSize},
+			}
+		case facetCategory:
+			// 按文章直属分类统计
+			aggs[facet] = map[string]interface{}{
+				"terms": map[string]interface{}{"field": "category_id", "size": facetSize},
+			}
+		case facetMonth:
+			aggs[facet] = map[string]interface{}{
+				"date_histogram": map[string]interface{}{
+					"field":             "published_at",
+					"calendar_interval": "month",
+					"format":            "yyyy-MM",
+					"min_doc_count":     1,
+					"order":             map[string]interface{}{"_key": "desc"},
+				},
+			}
+		case facetHasPicture:
+			aggs[facet] = map[string]interface{}{
+				"filters": map[string]interface{}{
+					"filters": map[string]interface{}{
+						"true":  hasPictureFilter(),
+						"false": noPictureFilter(),
+					},
+				},
+			}
+		default:
+			return nil, fmt.Errorf("%w: %s", errs.ErrInvalidFacet, facet)
+		}
+	}
+	return aggs, nil
+}
+
+// parseFacets 解析分面聚合结果
+func parseFacets(aggregations map[string]json.RawMessage) (*dtos.ArticleListFacets, error) {
+	facets := &dtos.ArticleListFacets{}
+	for name, raw := range aggregations {
+		var err error
+		switch name {
+		case facetTags:
+			facets.Tags, err = parseBuckets(raw)
+		case facetCategory:
+			facets.Category, err = parseBuckets(raw)
+		case facetMonth:
+			facets.Month, err = parseBuckets(raw)
+		case facetHasPicture:
+			var agg struct {
+				Buckets map[string]struct {
+					DocCount int64 `json:"doc_count"`
+				} `json:"buckets"`
+			}
+			if err = json.Unmarshal(raw, &agg); err == nil {
+				facets.HasPicture = []dtos.FacetBucket{
+					{Key: "true", Count: agg.Buckets["true"].DocCount},
+					{Key: "false", Count: agg.Buckets["false"].DocCount},
+				}
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error parsing the %s facet: %s", name, err)
+		}
+	}
+	return facets, nil
+}
+
+// parseBuckets 解析terms和date_histogram聚合的桶，有key_as_string时（日期、布尔字段）使用格式化后的key
+func parseBuckets(raw json.RawMessage) ([]dtos.FacetBucket, error) {
+	var agg struct {
+		Buckets []struct {
+			Key         json.RawMessage `json:"key"`
+			KeyAsString string          `json:"key_as_string"`
+			DocCount    int64           `json:"doc_count"`
+		} `json:"buckets"`
+	}
+	if err := json.Unmarshal(raw, &agg); err != nil {
+		return nil, err
+	}
+
+	buckets := make([]dtos.FacetBucket, len(agg.Buckets))
+	for i, bucket := range agg.Buckets {
+		key := bucket.KeyAsString
+		if key == "" {
+			var err error
+			if key, err = bucketKey(bucket.Key); err != nil {
+				return nil, err
+			}
+		}
+		buckets[i] = dtos.FacetBucket{Key: key, Count: bucket.DocCount}
+	}
+	return buckets, nil
+}
+
+// bucketKey 将桶的key格式化为字符串，keyword字段的key是字符串，数值字段的key是数字
+func bucketKey(raw json.RawMessage) (string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var key interface{}
+	if err := decoder.Decode(&key); err != nil {
+		return "", err
+	}
+
+	switch v := key.(type) {
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	default:
+		return "", fmt.Errorf("unsupported bucket key %s", raw)
+	}
+}
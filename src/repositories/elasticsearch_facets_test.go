@@ -0,0 +1,49 @@
+package repositories
+
+import (
+	"demo/src/dtos"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestParseBuckets(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want []dtos.FacetBucket
+	}{
+		{
+			name: "string keys",
+			raw:  `{"buckets":[{"key":"golang","doc_count":3},{"key":"web","doc_count":1}]}`,
+			want: []dtos.FacetBucket{{Key: "golang", Count: 3}, {Key: "web", Count: 1}},
+		},
+		{
+			name: "numeric keys",
+			raw:  `{"buckets":[{"key":12,"doc_count":5},{"key":9007199254740993,"doc_count":1}]}`,
+			want: []dtos.FacetBucket{{Key: "12", Count: 5}, {Key: "9007199254740993", Count: 1}},
+		},
+		{
+			name: "boolean keys",
+			raw:  `{"buckets":[{"key":1,"key_as_string":"true","doc_count":2},{"key":false,"doc_count":4}]}`,
+			want: []dtos.FacetBucket{{Key: "true", Count: 2}, {Key: "false", Count: 4}},
+		},
+		{
+			name: "date histogram keys",
+			raw:  `{"buckets":[{"key":1704067200000,"key_as_string":"2024-01","doc_count":7}]}`,
+			want: []dtos.FacetBucket{{Key: "2024-01", Count: 7}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseBuckets(json.RawMessage(tt.raw))
+			if err != nil {
+				t.Fatalf("parseBuckets() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseBuckets() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,253 @@
+package repositories
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// 文章索引通过别名articleIndex访问，实际的索引按映射和分析器配置生成版本号，
+// 配置变化时由migrate-index命令新建索引、从MySQL重建文档后原子地切换别名，服务启动时不修改索引
+
+// ArticleIndexAlias 文章索引的别名，旧版本直接以该名称创建索引
+const ArticleIndexAlias = articleIndex
+
+// articleIndexBody 创建文章索引的配置
+func articleIndexBody() map[string]interface{} {
+	return map[string]interface{}{
+		"settings": map[string]interface{}{"analysis": articleAnalysisSettings()},
+		"mappings": map[string]interface{}{"properties": articleMappingProperties()},
+	}
+}
+
+// ArticleIndexName 当前代码和配置期望的文章索引名，由映射和分析器配置的摘要生成；
+// 同义词和停用词在同义词文件中在线更新，不参与版本计算
+func ArticleIndexName() string {
+	data, _ := json.Marshal(articleIndexBody())
+	sum := sha256.Sum256(data)
+	return articleIndex + "_" + hex.EncodeToString(sum[:])[:12]
+}
+
+// CurrentArticleIndex 获取别名articleIndex指向的索引名，旧版本直接以articleIndex为名创建的索引返回articleIndex，
+// 索引不存在时返回空字符串
+func (repo *ElasticsearchRepository) CurrentArticleIndex(ctx context.Context) (string, error) {
+	res, err := repo.client.Indices.GetAlias(
+		repo.client.Indices.GetAlias.WithContext(ctx),
+		repo.client.Indices.GetAlias.WithName(articleIndex),
+	)
+	if err != nil {
+		return "", err
+	}
+	if res.StatusCode == http.StatusNotFound {
+		res.Body.Close()
+		return repo.legacyArticleIndex(ctx)
+	}
+	if res.IsError() {
+		return "", checkResponse(res, "getting article index alias")
+	}
+	defer res.Body.Close()
+
+	var aliases map[string]interface{}
+	if err := json.NewDecoder(res.Body).Decode(&aliases); err != nil {
+		return "", fmt.Errorf("error parsing the response body: %s", err)
+	}
+	for index := range aliases {
+		return index, nil
+	}
+	return "", nil
+}
+
+// legacyArticleIndex 别名不存在时判断是否存在名为articleIndex的旧索引
+func (repo *ElasticsearchRepository) legacyArticleIndex(ctx context.Context) (string, error) {
+	res, err := repo.client.Indices.Exists([]string{articleIndex}, repo.client.Indices.Exists.WithContext(ctx))
+	if err != nil {
+		return "", err
+	}
+	res.Body.Close()
+	if res.StatusCode == http.StatusNotFound {
+		return "", nil
+	}
+	return articleIndex, nil
+}
+
+// CreateArticleIndex 按当前配置创建文章索引，同名索引已存在（上次迁移中断留下）时先删除；
+// 创建时各ES节点需要能读取到同义词文件
+func (repo *ElasticsearchRepository) CreateArticleIndex(ctx context.Context, index string) error {
+	if err := repo.DeleteArticleIndex(ctx, index); err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(articleIndexBody())
+	if err != nil {
+		return err
+	}
+	res, err := repo.client.Indices.Create(index,
+		repo.client.Indices.Create.WithContext(ctx),
+		repo.client.Indices.Create.WithBody(bytes.NewReader(body)),
+	)
+	if err != nil {
+		return err
+	}
+	return checkResponse(res, "creating article index")
+}
+
+// DeleteArticleIndex 删除指定的文章索引，索引不存在时不报错
+func (repo *ElasticsearchRepository) DeleteArticleIndex(ctx context.Context, index string) error {
+	res, err := repo.client.Indices.Delete([]string{index},
+		repo.client.Indices.Delete.WithContext(ctx),
+		repo.client.Indices.Delete.WithIgnoreUnavailable(true),
+	)
+	if err != nil {
+		return err
+	}
+	return checkResponse(res, "deleting article index")
+}
+
+// SwitchArticleIndex 将别名articleIndex从from原子地切换到to；from为旧版本的同名索引时在同一请求中删除，
+// 为其他版本的索引时保留，确认无误后可手动删除
+func (repo *ElasticsearchRepository) SwitchArticleIndex(ctx context.Context, from, to string) error {
+	actions := []interface{}{}
+	switch from {
+	case "":
+	case articleIndex:
+		actions = append(actions, map[string]interface{}{"remove_index": map[string]interface{}{"index": from}})
+	default:
+		actions = append(actions, map[string]interface{}{"remove": map[string]interface{}{"index": from, "alias": articleIndex}})
+	}
+	actions = append(actions, map[string]interface{}{"add": map[string]interface{}{"index": to, "alias": articleIndex}})
+
+	body, err := json.Marshal(map[string]interface{}{"actions": actions})
+	if err != nil {
+		return err
+	}
+	res, err := repo.client.Indices.UpdateAliases(bytes.NewReader(body), repo.client.Indices.UpdateAliases.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	return checkResponse(res, "switching article index alias")
+}
+
+// IndexArticlesInto 使用_bulk接口将完整的文章文档写入指定索引，用于重建索引
+func (repo *ElasticsearchRepository) IndexArticlesInto(ctx context.Context, index string, docs []ArticleBulkDocument) ([]error, error) {
+	for i := range docs {
+		docs[i].Created = true
+	}
+	return repo.bulkSaveArticles(ctx, index, docs)
+}
+
+// RefreshArticleIndex 刷新指定的文章索引
+func (repo *ElasticsearchRepository) RefreshArticleIndex(ctx context.Context, index string) error {
+	res, err := repo.client.Indices.Refresh(
+		repo.client.Indices.Refresh.WithContext(ctx),
+		repo.client.Indices.Refresh.WithIndex(index),
+	)
+	if err != nil {
+		return err
+	}
+	return checkResponse(res, "refreshing article index")
+}
+
+// ScanArticleIndexIDs 按ID顺序逐批获取指定索引中的全部文章ID，用于重建后清理MySQL中已删除的文章
+func (repo *ElasticsearchRepository) ScanArticleIndexIDs(ctx context.Context, index string, batchSize int, fn func(ids []uint64) error) error {
+	var searchAfter []interface{}
+	for {
+		query := map[string]interface{}{
+			"size":             batchSize,
+			"_source":          false,
+			"track_total_hits": false,
+			"sort":             []interface{}{map[string]interface{}{"id": "asc"}},
+		}
+		if searchAfter != nil {
+			query["search_after"] = searchAfter
+		}
+
+		var buf bytes.Buffer
+		if err := json.NewEncoder(&buf).Encode(query); err != nil {
+			return err
+		}
+		res, err := repo.client.Search(
+			repo.client.Search.WithContext(ctx),
+			repo.client.Search.WithIndex(index),
+			repo.client.Search.WithBody(&buf),
+		)
+		if err != nil {
+			return err
+		}
+		if res.IsError() {
+			return checkResponse(res, "scanning article index")
+		}
+
+		var esResponse struct {
+			Hits struct {
+				Hits []struct {
+					ID   string        `json:"_id"`
+					Sort []interface{} `json:"sort"`
+				} `json:"hits"`
+			} `json:"hits"`
+		}
+		decoder := json.NewDecoder(res.Body)
+		decoder.UseNumber()
+		err = decoder.Decode(&esResponse)
+		res.Body.Close()
+		if err != nil {
+			return fmt.Errorf("error parsing the response body: %s", err)
+		}
+
+		hits := esResponse.Hits.Hits
+		if len(hits) == 0 {
+			return nil
+		}
+		ids := make([]uint64, len(hits))
+		for i, hit := range hits {
+			if ids[i], err = strconv.ParseUint(hit.ID, 10, 64); err != nil {
+				return fmt.Errorf("invalid article ID %q in index: %w", hit.ID, err)
+			}
+		}
+		if err := fn(ids); err != nil {
+			return err
+		}
+		if len(hits) < batchSize {
+			return nil
+		}
+		searchAfter = hits[len(hits)-1].Sort
+	}
+}
+
+// DeleteArticlesFrom 从指定索引中批量删除文章，文档不存在时忽略
+func (repo *ElasticsearchRepository) DeleteArticlesFrom(ctx context.Context, index string, ids []uint64) error {
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	for _, id := range ids {
+		meta := map[string]interface{}{"_index": index, "_id": strconv.FormatUint(id, 10)}
+		if err := encoder.Encode(map[string]interface{}{"delete": meta}); err != nil {
+			return err
+		}
+	}
+
+	res, err := repo.client.Bulk(&body, repo.client.Bulk.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	if res.IsError() {
+		return checkResponse(res, "deleting articles")
+	}
+	defer res.Body.Close()
+
+	var result bulkResponse
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return err
+	}
+	for _, item := range result.Items {
+		for _, status := range item {
+			if status.Error != nil && status.Status != http.StatusNotFound {
+				return fmt.Errorf("error deleting article! status: %d type: %s reason: %s", status.Status, status.Error.Type, status.Error.Reason)
+			}
+		}
+	}
+	return nil
+}
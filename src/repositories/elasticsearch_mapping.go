@@ -0,0 +1,139 @@
+package repositories
+
+import (
+	"context"
+	"demo/src/common/env"
+	"demo/src/models"
+	"strings"
+)
+
+// 文章索引使用的自定义分析器和过滤器名称
+const (
+	articleTextAnalyzer   = "article_text"         // 正文索引时使用
+	articleSearchAnalyzer = "article_search"       // 正文检索时使用，在索引分析器基础上增加同义词和停用词
+	titleSearchAnalyzer   = "article_title_search" // 标题和摘要检索时使用，standard分析器加同义词和停用词
+	articleStopFilter     = "article_stopwords"    // 停用词过滤器，去除同义词文件中停用词替换成的占位词
+	articleSynonymFilter  = "article_synonyms"     // 同义词过滤器，从同义词文件加载，可在线重新加载
+)
+
+// SearchTerms 检索时使用的同义词和停用词
+type SearchTerms struct {
+	Synonyms  []models.StringList // 每组互为同义词
+	Stopwords []string
+}
+
+// keywordSubField 与ES动态映射一致的字符串字段定义（text + keyword子字段），兼容已存在的索引
+var keywordSubField = map[string]interface{}{
+	"type": "text",
+	"fields": map[string]interface{}{
+		"keyword": map[string]interface{}{"type": "keyword", "ignore_above": 256},
+	},
+}
+
+// titleField 标题字段定义，在keywordSubField基础上增加search_as_you_type子字段用于输入联想，
+// 检索时使用同义词和停用词
+var titleField = map[string]interface{}{
+	"type":            "text",
+	"analyzer":        "standard",
+	"search_analyzer": titleSearchAnalyzer,
+	"fields": map[string]interface{}{
+		"keyword": map[string]interface{}{"type": "keyword", "ignore_above": 256},
+		"suggest": map[string]interface{}{"type": "search_as_you_type"},
+	},
+}
+
+// summaryField 摘要字段定义，检索时使用同义词和停用词
+var summaryField = map[string]interface{}{
+	"type":            "text",
+	"analyzer":        "standard",
+	"search_analyzer": titleSearchAnalyzer,
+	"fields": map[string]interface{}{
+		"keyword": map[string]interface{}{"type": "keyword", "ignore_above": 256},
+	},
+}
+
+// articleMappingProperties 文章索引的字段映射
+func articleMappingProperties() map[string]interface{} {
+	return map[string]interface{}{
+		"id":             map[string]interface{}{"type": "long"},
+		"title":          titleField,
+		"slug":           map[string]interface{}{"type": "keyword"},
+		"picture":        keywordSubField,
+		"summary":        summaryField,
+		"content_format": keywordSubField,
+		"tags":           map[string]interface{}{"type": "keyword", "normalizer": "lowercase"},
+		"category_id":    map[string]interface{}{"type": "long"},
+		"category_path":  map[string]interface{}{"type": "long"},
+		"author_id":      map[string]interface{}{"type": "long"},
+		"author_name":    keywordSubField,
+		"external_id":    map[string]interface{}{"type": "keyword"},
+		"status":         map[string]interface{}{"type": "keyword"},
+		"published_at":   map[string]interface{}{"type": "date"},
+		"created_at":     map[string]interface{}{"type": "date"},
+		"updated_at":     map[string]interface{}{"type": "date"},
+		"content": map[string]interface{}{
+			"type":            "text",
+			"analyzer":        articleTextAnalyzer,
+			"search_analyzer": articleSearchAnalyzer,
+		},
+	}
+}
+
+// articleAnalysisSettings 文章索引的分析器配置，分词器和过滤器可通过环境变量调整：
+// 默认使用standard分词器加cjk_bigram，中文按二元组切分、英文按单词切分；
+// 安装了IK等分词插件时可设置ES_TEXT_TOKENIZER=ik_max_word。
+// 同义词和停用词只在检索时生效，从各ES节点config目录下的同义词文件（ES_SYNONYMS_PATH）加载，
+// 修改后重新加载检索分析器即可，无需关闭或重建索引
+func articleAnalysisSettings() map[string]interface{} {
+	tokenizer := env.String("ES_TEXT_TOKENIZER", "standard")
+	filters := []interface{}{}
+	for _, filter := range strings.Split(env.String("ES_TEXT_FILTERS", "cjk_width,lowercase,cjk_bigram"), ",") {
+		if filter = strings.TrimSpace(filter); filter != "" {
+			filters = append(filters, filter)
+		}
+	}
+	searchFilters := append(append([]interface{}{}, filters...), articleSynonymFilter, articleStopFilter)
+
+	return map[string]interface{}{
+		"filter": map[string]interface{}{
+			articleSynonymFilter: map[string]interface{}{
+				"type":          "synonym_graph",
+				"synonyms_path": env.String("ES_SYNONYMS_PATH", "analysis/article_synonyms.txt"),
+				"updateable":    true,
+				"lenient":       true, // 忽略无法解析的同义词，避免一条错误规则导致重新加载失败
+			},
+			articleStopFilter: map[string]interface{}{
+				"type":      "stop",
+				"stopwords": []string{stopwordPlaceholder},
+			},
+		},
+		"analyzer": map[string]interface{}{
+			articleTextAnalyzer: map[string]interface{}{
+				"type":      "custom",
+				"tokenizer": tokenizer,
+				"filter":    filters,
+			},
+			articleSearchAnalyzer: map[string]interface{}{
+				"type":      "custom",
+				"tokenizer": tokenizer,
+				"filter":    searchFilters,
+			},
+			titleSearchAnalyzer: map[string]interface{}{
+				"type":      "custom",
+				"tokenizer": "standard",
+				"filter":    []interface{}{"lowercase", articleSynonymFilter, articleStopFilter},
+			},
+		},
+	}
+}
+
+// ReloadSearchAnalyzers 重新加载文章索引的检索分析器，使同义词文件的修改生效
+func (repo *ElasticsearchRepository) ReloadSearchAnalyzers(ctx context.Context) error {
+	res, err := repo.client.Indices.ReloadSearchAnalyzers([]string{articleIndex},
+		repo.client.Indices.ReloadSearchAnalyzers.WithContext(ctx),
+	)
+	if err != nil {
+		return err
+	}
+	return checkResponse(res, "reloading article search analyzers")
+}
@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"context"
+	"demo/src/common/env"
+	"log"
+	"strings"
+)
+
+// ES写入后的刷新策略
+const (
+	RefreshTrue    = "true"     // 立即刷新，写入后马上可以搜索到，但每次写入都会产生新的segment
+	RefreshWaitFor = "wait_for" // 等待下一次定时刷新后返回，不强制刷新
+	RefreshFalse   = "false"    // 不等待刷新，按index.refresh_interval定时可见
+)
+
+// 使用刷新策略的写入操作
+const (
+	refreshOpAdd     = "add"      // 新增文章
+	refreshOpUpdate  = "update"   // 更新文章及文章状态
+	refreshOpDelete  = "delete"   // 删除文章
+	refreshOpBulk    = "bulk"     // 批量写入，全部写入后刷新一次
+	refreshOpByQuery = "by_query" // 批量更新作者名称和分类路径，update_by_query不支持wait_for，按true处理
+)
+
+// defaultRefreshModes 各操作默认的刷新策略
+var defaultRefreshModes = map[string]string{
+	refreshOpAdd:     RefreshWaitFor,
+	refreshOpUpdate:  RefreshWaitFor,
+	refreshOpDelete:  RefreshWaitFor,
+	refreshOpBulk:    RefreshTrue,
+	refreshOpByQuery: RefreshTrue,
+}
+
+// refreshModes 各操作的刷新策略，由InitRefreshModes从环境变量加载
+var refreshModes = defaultRefreshModes
+
+// ValidRefreshMode 判断是否为支持的刷新策略
+func ValidRefreshMode(mode string) bool {
+	return mode == RefreshTrue || mode == RefreshWaitFor || mode == RefreshFalse
+}
+
+// InitRefreshModes 从环境变量ES_REFRESH_ADD、ES_REFRESH_UPDATE、ES_REFRESH_DELETE、ES_REFRESH_BULK、ES_REFRESH_BY_QUERY加载各操作的刷新策略
+func InitRefreshModes() {
+	modes := make(map[string]string, len(defaultRefreshModes))
+	for op, defaultMode := range defaultRefreshModes {
+		key := "ES_REFRESH_" + strings.ToUpper(op)
+		mode := env.String(key, defaultMode)
+		if !ValidRefreshMode(mode) {
+			log.Fatalf("Invalid %s: %q, expected true, wait_for or false", key, mode)
+		}
+		modes[op] = mode
+	}
+	refreshModes = modes
+}
+
+type refreshContextKey struct{}
+
+// WithRefresh 为本次请求的ES写入指定刷新策略，覆盖各操作的默认配置
+func WithRefresh(ctx context.Context, mode string) context.Context {
+	return context.WithValue(ctx, refreshContextKey{}, mode)
+}
+
+// refreshMode 获取写入操作使用的刷新策略，请求指定的策略优先
+func refreshMode(ctx context.Context, op string) string {
+	if mode, ok := ctx.Value(refreshContextKey{}).(string); ok && mode != "" {
+		return mode
+	}
+	return refreshModes[op]
+}
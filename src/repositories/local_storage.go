@@ -0,0 +1,116 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// LocalStorage 本地文件系统存储
+type LocalStorage struct {
+	root     string
+	baseURL  string
+	baseHost string // baseURL中的域名，baseURL为相对路径时为空
+	basePath string // baseURL中的路径
+}
+
+func NewLocalStorage(root, baseURL string) (*LocalStorage, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, err
+	}
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	return &LocalStorage{
+		root:     root,
+		baseURL:  baseURL,
+		baseHost: u.Host,
+		basePath: strings.TrimSuffix(u.Path, "/"),
+	}, nil
+}
+
+// Root 本地存储根目录
+func (s *LocalStorage) Root() string {
+	return s.root
+}
+
+// BaseURL 本地存储文件的访问地址前缀
+func (s *LocalStorage) BaseURL() string {
+	return s.baseURL
+}
+
+// BasePath 本地存储文件的访问路径前缀，不含域名
+func (s *LocalStorage) BasePath() string {
+	return s.basePath
+}
+
+// Save 保存文件，先写入临时文件再重命名，避免读到不完整的文件
+func (s *LocalStorage) Save(ctx context.Context, key string, r io.Reader) error {
+	filename := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(filename), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(filename), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), filename)
+}
+
+// Exists 检查文件是否存在
+func (s *LocalStorage) Exists(ctx context.Context, key string) (bool, error) {
+	_, err := os.Stat(s.path(key))
+	if err == nil {
+		return true, nil
+	}
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	return false, err
+}
+
+// URL 返回文件访问地址
+func (s *LocalStorage) URL(key string) string {
+	return s.baseURL + "/" + key
+}
+
+// Key 解析访问地址对应的key，支持相对路径和带域名的完整地址；完整地址的域名需要与baseURL一致，
+// baseURL为相对路径时只接受相对路径，其他网站路径相同的图片不会被当作本地文件
+func (s *LocalStorage) Key(fileURL string) (string, bool) {
+	u, err := url.Parse(fileURL)
+	if err != nil {
+		return "", false
+	}
+	if u.Host != "" && !strings.EqualFold(u.Host, s.baseHost) {
+		return "", false
+	}
+	key, ok := strings.CutPrefix(u.Path, s.basePath+"/")
+	if !ok || key == "" {
+		return "", false
+	}
+	return key, true
+}
+
+// path 将key转换为本地文件路径，清理路径防止跳出根目录
+func (s *LocalStorage) path(key string) string {
+	return filepath.Join(s.root, filepath.FromSlash(path.Clean("/"+key)))
+}
@@ -0,0 +1,37 @@
+package repositories
+
+import "testing"
+
+func TestLocalStorageKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		baseURL string
+		fileURL string
+		wantKey string
+		wantOK  bool
+	}{
+		{name: "relative", baseURL: "/uploads", fileURL: "/uploads/2024/01/a.jpg", wantKey: "2024/01/a.jpg", wantOK: true},
+		{name: "relative base with trailing slash", baseURL: "/uploads/", fileURL: "/uploads/a.jpg", wantKey: "a.jpg", wantOK: true},
+		{name: "foreign host with same path", baseURL: "/uploads", fileURL: "https://example.com/uploads/a.jpg"},
+		{name: "protocol-relative foreign host", baseURL: "/uploads", fileURL: "//example.com/uploads/a.jpg"},
+		{name: "other path", baseURL: "/uploads", fileURL: "/static/a.jpg"},
+		{name: "base only", baseURL: "/uploads", fileURL: "/uploads/"},
+		{name: "absolute base", baseURL: "https://cdn.example.com/uploads", fileURL: "https://CDN.example.com/uploads/a.jpg", wantKey: "a.jpg", wantOK: true},
+		{name: "absolute base relative url", baseURL: "https://cdn.example.com/uploads", fileURL: "/uploads/a.jpg", wantKey: "a.jpg", wantOK: true},
+		{name: "absolute base other host", baseURL: "https://cdn.example.com/uploads", fileURL: "https://evil.example.com/uploads/a.jpg"},
+		{name: "invalid url", baseURL: "/uploads", fileURL: "http://%zz/uploads/a.jpg"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage, err := NewLocalStorage(t.TempDir(), tt.baseURL)
+			if err != nil {
+				t.Fatalf("NewLocalStorage() error = %v", err)
+			}
+			key, ok := storage.Key(tt.fileURL)
+			if key != tt.wantKey || ok != tt.wantOK {
+				t.Errorf("Key(%q) = %q, %v, want %q, %v", tt.fileURL, key, ok, tt.wantKey, tt.wantOK)
+			}
+		})
+	}
+}
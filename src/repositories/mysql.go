@@ -1,12 +1,16 @@
 package repositories
 
 import (
+	"demo/src/errs"
 	"demo/src/models"
+	"errors"
 	"fmt"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"log"
 	"os"
+	"time"
 )
 
 type MySQLRepository struct {
@@ -42,21 +46,32 @@ func InitDB() *gorm.DB {
 // AddArticle 新增文章到DB
 func (repo *MySQLRepository) AddArticle(article *models.Article, articleContent *models.ArticleContent) error {
 	return repo.db.Transaction(func(tx *gorm.DB) error {
-		// 新增文章
-		if err := tx.Create(article).Error; err != nil {
-			return err
-		}
+		return addArticle(tx, article, articleContent)
+	})
+}
 
-		// 设置文章内容的ArticleID
-		articleContent.ArticleID = article.ID
+// addArticle 在事务中新增文章、文章内容、标签关联和初始版本
+func addArticle(tx *gorm.DB, article *models.Article, articleContent *models.ArticleContent) error {
+	// 新增文章
+	if err := tx.Create(article).Error; err != nil {
+		return slugConflict(err)
+	}
 
-		// 新增文章内容
-		if err := tx.Create(articleContent).Error; err != nil {
-			return err
-		}
+	// 设置文章内容的ArticleID
+	articleContent.ArticleID = article.ID
 
-		return nil // 如果都添加成功，返回nil提交事务
-	})
+	// 新增文章内容
+	if err := tx.Create(articleContent).Error; err != nil {
+		return err
+	}
+
+	// 保存文章标签
+	if err := saveArticleTags(tx, article.ID, article.Tags); err != nil {
+		return err
+	}
+
+	// 记录初始版本
+	return addArticleRevision(tx, article, articleContent)
 }
 
 // ArticleExists 检查文章是否存在
@@ -66,32 +81,225 @@ func (repo *MySQLRepository) ArticleExists(articleID uint64) (bool, error) {
 	return count > 0, err
 }
 
+// PublishedArticleIDs 返回ids中当前为已发布状态的文章ID
+func (repo *MySQLRepository) PublishedArticleIDs(ids []uint64) (map[uint64]bool, error) {
+	var found []uint64
+	err := repo.db.Model(&models.Article{}).
+		Where("id IN ? AND status = ?", ids, models.ArticleStatusPublished).
+		Pluck("id", &found).Error
+	if err != nil {
+		return nil, err
+	}
+
+	published := make(map[uint64]bool, len(found))
+	for _, id := range found {
+		published[id] = true
+	}
+	return published, nil
+}
+
+// FindArticle 获取文章（不含内容和标签）
+func (repo *MySQLRepository) FindArticle(articleID uint64) (*models.Article, error) {
+	var article models.Article
+	if err := repo.db.Where("id = ?", articleID).Take(&article).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errs.ErrArticleNotFound
+		}
+		return nil, err
+	}
+	return &article, nil
+}
+
+// GetArticle 获取文章及文章内容
+func (repo *MySQLRepository) GetArticle(articleID uint64) (*models.Article, *models.ArticleContent, error) {
+	article, err := repo.FindArticle(articleID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var articleContent models.ArticleContent
+	if err := repo.db.Where("article_id = ?", articleID).Take(&articleContent).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, err
+		}
+		articleContent.ArticleID = articleID // 没有内容记录时返回空内容
+	}
+
+	tags, err := repo.GetArticleTags(articleID)
+	if err != nil {
+		return nil, nil, err
+	}
+	article.Tags = tags
+
+	if article.AuthorID > 0 {
+		if article.AuthorName, err = repo.GetUserName(article.AuthorID); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return article, &articleContent, nil
+}
+
+// GetArticleTags 获取文章的标签名称
+func (repo *MySQLRepository) GetArticleTags(articleID uint64) ([]string, error) {
+	tags := []string{}
+	err := repo.db.Model(&models.Tag{}).
+		Joins("JOIN article_tag ON article_tag.tag_id = tag.id").
+		Where("article_tag.article_id = ?", articleID).
+		Order("tag.name").
+		Pluck("tag.name", &tags).Error
+	return tags, err
+}
+
+// saveArticleTags 替换文章的标签关联，不存在的标签会先创建
+func saveArticleTags(tx *gorm.DB, articleID uint64, tagNames []string) error {
+	// 删除旧的标签关联
+	if err := tx.Where("article_id = ?", articleID).Delete(&models.ArticleTag{}).Error; err != nil {
+		return err
+	}
+
+	if len(tagNames) == 0 {
+		return nil
+	}
+
+	// 创建不存在的标签，已存在的忽略
+	tags := make([]models.Tag, len(tagNames))
+	for i, name := range tagNames {
+		tags[i] = models.Tag{Name: name}
+	}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&tags).Error; err != nil {
+		return err
+	}
+
+	// 查询标签ID并建立关联
+	var tagIDs []uint64
+	if err := tx.Model(&models.Tag{}).Where("name IN ?", tagNames).Pluck("id", &tagIDs).Error; err != nil {
+		return err
+	}
+
+	articleTags := make([]models.ArticleTag, len(tagIDs))
+	for i, tagID := range tagIDs {
+		articleTags[i] = models.ArticleTag{ArticleID: articleID, TagID: tagID}
+	}
+	return tx.Create(&articleTags).Error
+}
+
 // UpdateArticle 更新文章
 func (repo *MySQLRepository) UpdateArticle(article *models.Article, articleContent *models.ArticleContent) error {
 	return repo.db.Transaction(func(tx *gorm.DB) error {
-		// 更新article表中的文章
-		if err := tx.Model(&models.Article{}).
-			Where("id = ?", article.ID).
-			Select("Title", "Picture", "Summary", "UpdatedAt").
-			Updates(models.Article{
-				Title:     article.Title,
-				Picture:   article.Picture,
-				Summary:   article.Summary,
-				UpdatedAt: article.UpdatedAt,
-			}).Error; err != nil {
-			return err
-		}
+		return updateArticle(tx, article, articleContent)
+	})
+}
+
+// updateArticle 在事务中更新文章、文章内容和标签关联，并追加新版本
+func updateArticle(tx *gorm.DB, article *models.Article, articleContent *models.ArticleContent) error {
+	// 查询原访问标识，变化时保留为跳转
+	var oldSlug string
+	if err := tx.Model(&models.Article{}).Where("id = ?", article.ID).Select("slug").Scan(&oldSlug).Error; err != nil {
+		return err
+	}
+	if err := saveSlugRedirect(tx, article.ID, oldSlug, article.Slug); err != nil {
+		return err
+	}
+
+	// 更新article表中的文章
+	if err := tx.Model(&models.Article{}).
+		Where("id = ?", article.ID).
+		Select("Title", "Slug", "Picture", "PictureVariants", "Summary", "ContentFormat", "CategoryID", "Status", "PublishedAt", "UpdatedAt").
+		Updates(models.Article{
+			Title:           article.Title,
+			Slug:            article.Slug,
+			Picture:         article.Picture,
+			PictureVariants: article.PictureVariants,
+			Summary:         article.Summary,
+			ContentFormat:   article.ContentFormat,
+			CategoryID:      article.CategoryID,
+			Status:          article.Status,
+			PublishedAt:     article.PublishedAt,
+			UpdatedAt:       article.UpdatedAt,
+		}).Error; err != nil {
+		return slugConflict(err)
+	}
+
+	// 更新article_content表中的文章内容
+	if err := tx.Model(&models.ArticleContent{}).
+		Where("article_id = ?", articleContent.ArticleID).
+		Select("Content", "ContentHTML").
+		Updates(models.ArticleContent{
+			Content:     articleContent.Content,
+			ContentHTML: articleContent.ContentHTML,
+		}).Error; err != nil {
+		return err
+	}
+
+	// 更新文章标签
+	if err := saveArticleTags(tx, article.ID, article.Tags); err != nil {
+		return err
+	}
+
+	// 追加新版本
+	return addArticleRevision(tx, article, articleContent)
+}
+
+// UpdateArticleStatus 更新文章状态和发布时间
+func (repo *MySQLRepository) UpdateArticleStatus(articleID uint64, status string, publishedAt *time.Time) error {
+	return repo.db.Model(&models.Article{}).
+		Where("id = ?", articleID).
+		Select("Status", "PublishedAt", "UpdatedAt").
+		Updates(models.Article{
+			Status:      status,
+			PublishedAt: publishedAt,
+			UpdatedAt:   time.Now(),
+		}).Error
+}
 
-		// 更新article_content表中的文章内容
-		if err := tx.Model(&models.ArticleContent{}).
-			Where("article_id = ?", articleContent.ArticleID).
-			Select("Content").
-			Updates(models.ArticleContent{
-				Content: articleContent.Content,
-			}).Error; err != nil {
-			return err
+// ListDueScheduledArticles 按(published_at, id)顺序获取已到发布时间的定时发布文章，after不为nil时从该文章之后继续获取
+func (repo *MySQLRepository) ListDueScheduledArticles(now time.Time, after *models.Article, limit int) ([]models.Article, error) {
+	query := repo.db.Where("status = ? AND published_at <= ?", models.ArticleStatusScheduled, now)
+	if after != nil {
+		query = query.Where("(published_at > ? OR (published_at = ? AND id > ?))", after.PublishedAt, after.PublishedAt, after.ID)
+	}
+
+	var articles []models.Article
+	err := query.Order("published_at").Order("id").
+		Limit(limit).
+		Find(&articles).Error
+	return articles, err
+}
+
+// PublishScheduledArticle 将定时发布文章标记为已发布，仅当文章仍处于定时发布状态时生效，返回是否更新成功
+func (repo *MySQLRepository) PublishScheduledArticle(articleID uint64) (bool, error) {
+	result := repo.db.Model(&models.Article{}).
+		Where("id = ? AND status = ?", articleID, models.ArticleStatusScheduled).
+		Select("Status", "UpdatedAt").
+		Updates(models.Article{
+			Status:    models.ArticleStatusPublished,
+			UpdatedAt: time.Now(),
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// DeleteArticle 删除文章及其内容、标签关联、版本和历史访问标识
+func (repo *MySQLRepository) DeleteArticle(articleID uint64) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		for _, model := range []interface{}{
+			&models.ArticleContent{},
+			&models.ArticleTag{},
+			&models.ArticleRevision{},
+			&models.ArticleSlugRedirect{},
+		} {
+			if err := tx.Where("article_id = ?", articleID).Delete(model).Error; err != nil {
+				return err
+			}
 		}
 
-		return nil // 如果都更新成功，返回nil提交事务
+		result := tx.Where("id = ?", articleID).Delete(&models.Article{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errs.ErrArticleNotFound
+		}
+		return nil
 	})
 }
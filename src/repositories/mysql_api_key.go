@@ -0,0 +1,48 @@
+package repositories
+
+import (
+	"demo/src/errs"
+	"demo/src/models"
+	"errors"
+	"gorm.io/gorm"
+	"time"
+)
+
+// AddAPIKey 新增API Key
+func (repo *MySQLRepository) AddAPIKey(apiKey *models.APIKey) error {
+	return repo.db.Create(apiKey).Error
+}
+
+// ListAPIKeys 获取全部API Key
+func (repo *MySQLRepository) ListAPIKeys() ([]models.APIKey, error) {
+	var apiKeys []models.APIKey
+	err := repo.db.Order("id").Find(&apiKeys).Error
+	return apiKeys, err
+}
+
+// FindActiveAPIKey 根据密钥哈希获取未吊销的API Key
+func (repo *MySQLRepository) FindActiveAPIKey(keyHash string) (*models.APIKey, error) {
+	var apiKey models.APIKey
+	if err := repo.db.Where("key_hash = ? AND revoked_at IS NULL", keyHash).Take(&apiKey).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errs.ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+	return &apiKey, nil
+}
+
+// RevokeAPIKey 吊销API Key，已吊销的保持原吊销时间
+func (repo *MySQLRepository) RevokeAPIKey(apiKeyID uint64) error {
+	var count int64
+	if err := repo.db.Model(&models.APIKey{}).Where("id = ?", apiKeyID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return errs.ErrAPIKeyNotFound
+	}
+
+	return repo.db.Model(&models.APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", apiKeyID).
+		Update("revoked_at", time.Now()).Error
+}
@@ -0,0 +1,42 @@
+package repositories
+
+import (
+	"demo/src/models"
+	"fmt"
+	"gorm.io/gorm"
+)
+
+// ArticleWrite 批量写入的一篇文章，Article.ID为0时新增，否则更新
+type ArticleWrite struct {
+	Article        *models.Article
+	ArticleContent *models.ArticleContent
+}
+
+// SaveArticles 在一个事务中批量新增或更新文章，每篇文章使用单独的保存点，
+// 单篇失败时回滚到保存点并记录在返回的对应位置，不影响其他文章；事务本身失败时返回err
+func (repo *MySQLRepository) SaveArticles(writes []ArticleWrite) (itemErrs []error, err error) {
+	itemErrs = make([]error, len(writes))
+	err = repo.db.Transaction(func(tx *gorm.DB) error {
+		for i, write := range writes {
+			savePoint := fmt.Sprintf("article_%d", i)
+			if err := tx.SavePoint(savePoint).Error; err != nil {
+				return err
+			}
+
+			var writeErr error
+			if write.Article.ID == 0 {
+				writeErr = addArticle(tx, write.Article, write.ArticleContent)
+			} else {
+				writeErr = updateArticle(tx, write.Article, write.ArticleContent)
+			}
+			if writeErr != nil {
+				itemErrs[i] = writeErr
+				if err := tx.RollbackTo(savePoint).Error; err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	return itemErrs, err
+}
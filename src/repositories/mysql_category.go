@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"demo/src/errs"
+	"demo/src/models"
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ListCategories 获取全部分类
+func (repo *MySQLRepository) ListCategories() ([]models.Category, error) {
+	var categories []models.Category
+	err := repo.db.Order("id").Find(&categories).Error
+	return categories, err
+}
+
+// GetCategory 获取分类
+func (repo *MySQLRepository) GetCategory(categoryID uint64) (*models.Category, error) {
+	var category models.Category
+	if err := repo.db.Where("id = ?", categoryID).Take(&category).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errs.ErrCategoryNotFound
+		}
+		return nil, err
+	}
+	return &category, nil
+}
+
+// AddCategory 新增分类
+func (repo *MySQLRepository) AddCategory(category *models.Category) error {
+	return repo.db.Create(category).Error
+}
+
+// UpdateCategory 更新分类名称和父分类
+func (repo *MySQLRepository) UpdateCategory(category *models.Category) error {
+	return repo.db.Model(&models.Category{}).
+		Where("id = ?", category.ID).
+		Select("Name", "ParentID", "UpdatedAt").
+		Updates(models.Category{
+			Name:      category.Name,
+			ParentID:  category.ParentID,
+			UpdatedAt: category.UpdatedAt,
+		}).Error
+}
+
+// DeleteCategory 删除分类，分类下还有文章或子分类时拒绝删除
+func (repo *MySQLRepository) DeleteCategory(categoryID uint64) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		// 锁定分类行，避免检查期间被并发修改
+		var category models.Category
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", categoryID).Take(&category).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errs.ErrCategoryNotFound
+			}
+			return err
+		}
+
+		// 检查子分类
+		var count int64
+		if err := tx.Model(&models.Category{}).Where("parent_id = ?", categoryID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return errs.ErrCategoryHasChildren
+		}
+
+		// 检查分类下的文章
+		if err := tx.Model(&models.Article{}).Where("category_id = ?", categoryID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return errs.ErrCategoryHasArticles
+		}
+
+		return tx.Delete(&category).Error
+	})
+}
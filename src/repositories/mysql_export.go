@@ -0,0 +1,75 @@
+package repositories
+
+import "demo/src/models"
+
+// GetArticles 批量获取文章及文章内容，按ids的顺序返回，已删除的文章会被跳过
+func (repo *MySQLRepository) GetArticles(ids []uint64) ([]models.Article, map[uint64]models.ArticleContent, error) {
+	var found []models.Article
+	if err := repo.db.Where("id IN ?", ids).Find(&found).Error; err != nil {
+		return nil, nil, err
+	}
+
+	var contents []models.ArticleContent
+	if err := repo.db.Where("article_id IN ?", ids).Find(&contents).Error; err != nil {
+		return nil, nil, err
+	}
+	contentMap := make(map[uint64]models.ArticleContent, len(contents))
+	for _, content := range contents {
+		contentMap[content.ArticleID] = content
+	}
+
+	// 标签
+	var articleTags []struct {
+		ArticleID uint64
+		Name      string
+	}
+	if err := repo.db.Model(&models.Tag{}).
+		Select("article_tag.article_id, tag.name").
+		Joins("JOIN article_tag ON article_tag.tag_id = tag.id").
+		Where("article_tag.article_id IN ?", ids).
+		Order("tag.name").
+		Scan(&articleTags).Error; err != nil {
+		return nil, nil, err
+	}
+	tags := make(map[uint64][]string)
+	for _, tag := range articleTags {
+		tags[tag.ArticleID] = append(tags[tag.ArticleID], tag.Name)
+	}
+
+	// 作者名称
+	var authorIDs []uint64
+	for _, article := range found {
+		if article.AuthorID > 0 {
+			authorIDs = append(authorIDs, article.AuthorID)
+		}
+	}
+	authorNames := make(map[uint64]string)
+	if len(authorIDs) > 0 {
+		var users []models.User
+		if err := repo.db.Select("id", "name").Where("id IN ?", authorIDs).Find(&users).Error; err != nil {
+			return nil, nil, err
+		}
+		for _, user := range users {
+			authorNames[user.ID] = user.Name
+		}
+	}
+
+	byID := make(map[uint64]*models.Article, len(found))
+	for i := range found {
+		article := &found[i]
+		article.Tags = tags[article.ID]
+		if article.Tags == nil {
+			article.Tags = []string{}
+		}
+		article.AuthorName = authorNames[article.AuthorID]
+		byID[article.ID] = article
+	}
+
+	articles := make([]models.Article, 0, len(found))
+	for _, id := range ids {
+		if article, ok := byID[id]; ok {
+			articles = append(articles, *article)
+		}
+	}
+	return articles, contentMap, nil
+}
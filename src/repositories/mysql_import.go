@@ -0,0 +1,20 @@
+package repositories
+
+import "demo/src/models"
+
+// ExistingExternalIDs 查询已导入的原系统ID
+func (repo *MySQLRepository) ExistingExternalIDs(externalIDs []string) (map[string]bool, error) {
+	existing := make(map[string]bool)
+	if len(externalIDs) == 0 {
+		return existing, nil
+	}
+
+	var found []string
+	if err := repo.db.Model(&models.Article{}).Where("external_id IN ?", externalIDs).Pluck("external_id", &found).Error; err != nil {
+		return nil, err
+	}
+	for _, id := range found {
+		existing[id] = true
+	}
+	return existing, nil
+}
@@ -0,0 +1,32 @@
+package repositories
+
+import (
+	"demo/src/models"
+	"time"
+)
+
+// ListArticleIDs 按ID顺序获取afterID之后的文章ID，updatedSince不为零时只返回此后更新过的文章，用于重建索引
+func (repo *MySQLRepository) ListArticleIDs(afterID uint64, updatedSince time.Time, limit int) ([]uint64, error) {
+	query := repo.db.Model(&models.Article{}).Where("id > ?", afterID)
+	if !updatedSince.IsZero() {
+		query = query.Where("updated_at >= ?", updatedSince)
+	}
+
+	var ids []uint64
+	err := query.Order("id").Limit(limit).Pluck("id", &ids).Error
+	return ids, err
+}
+
+// ExistingArticleIDs 返回ids中仍存在于MySQL的文章ID
+func (repo *MySQLRepository) ExistingArticleIDs(ids []uint64) (map[uint64]bool, error) {
+	var found []uint64
+	if err := repo.db.Model(&models.Article{}).Where("id IN ?", ids).Pluck("id", &found).Error; err != nil {
+		return nil, err
+	}
+
+	existing := make(map[uint64]bool, len(found))
+	for _, id := range found {
+		existing[id] = true
+	}
+	return existing, nil
+}
@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"gorm.io/gorm"
+	"time"
+)
+
+// WithLock 持有MySQL命名锁（GET_LOCK）执行fn，用于多个实例之间串行执行；
+// 锁与数据库连接绑定，加锁、执行和释放在同一连接上完成，实例异常退出时连接断开锁自动释放
+func (repo *MySQLRepository) WithLock(name string, timeout time.Duration, fn func() error) error {
+	return repo.db.Connection(func(conn *gorm.DB) error {
+		var acquired sql.NullInt64
+		if err := conn.Raw("SELECT GET_LOCK(?, ?)", name, int(timeout.Seconds())).Row().Scan(&acquired); err != nil {
+			return err
+		}
+		if acquired.Int64 != 1 {
+			return fmt.Errorf("timed out waiting for lock %s", name)
+		}
+		defer conn.Exec("SELECT RELEASE_LOCK(?)", name)
+
+		return fn()
+	})
+}
@@ -0,0 +1,138 @@
+package repositories
+
+import (
+	"demo/src/models"
+	"fmt"
+	"gorm.io/gorm"
+)
+
+// 升级在init.sql之前创建的旧版本部署：新增的表由init.sql的CREATE TABLE IF NOT EXISTS创建，
+// 旧版本已存在的article和article_content表由migrate-db命令补充列和索引，每一步已完成时跳过，可以重复执行
+
+// schemaChange 旧版本的表需要补充的列或索引，定义与init.sql一致
+type schemaChange struct {
+	table      string
+	name       string
+	definition string
+}
+
+// legacyColumns 旧版本article和article_content表缺少的列；slug先以空字符串为默认值添加，生成后再去掉默认值
+var legacyColumns = []schemaChange{
+	{"article", "slug", "VARCHAR(191) NOT NULL DEFAULT '' COMMENT '由标题生成的唯一访问标识' AFTER `title`"},
+	{"article", "picture_variants", "JSON NULL COMMENT '图片缩略图及宽度版本' AFTER `picture`"},
+	{"article", "content_format", "VARCHAR(16) NOT NULL DEFAULT 'plain' COMMENT '内容格式：plain、markdown、html' AFTER `summary`"},
+	{"article", "category_id", "BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '所属分类ID，0表示未分类' AFTER `content_format`"},
+	{"article", "author_id", "BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '作者的用户ID，0表示没有作者' AFTER `category_id`"},
+	{"article", "external_id", "VARCHAR(191) NULL COMMENT '导入文章在原系统中的ID，用于去重' AFTER `author_id`"},
+	{"article", "status", "VARCHAR(16) NOT NULL DEFAULT 'published' COMMENT '文章状态：draft、scheduled、published、archived' AFTER `external_id`"},
+	{"article", "published_at", "DATETIME NULL COMMENT '发布时间，定时发布时为计划发布时间' AFTER `status`"},
+	{"article_content", "content_html", "LONGTEXT NOT NULL COMMENT '渲染并过滤后的HTML内容'"},
+}
+
+// legacyIndexes 旧版本article表缺少的索引，uk_slug在为已有文章生成访问标识后添加
+var legacyIndexes = []schemaChange{
+	{"article", "uk_slug", "UNIQUE KEY `uk_slug` (`slug`)"},
+	{"article", "uk_external_id", "UNIQUE KEY `uk_external_id` (`external_id`)"},
+	{"article", "idx_category_id", "KEY `idx_category_id` (`category_id`)"},
+	{"article", "idx_author_id", "KEY `idx_author_id` (`author_id`)"},
+	{"article", "idx_status_published_at", "KEY `idx_status_published_at` (`status`, `published_at`)"},
+}
+
+// MigrateLegacyColumns 为旧版本的表补充缺少的列，返回新增的列名（table.column）；
+// 已有文章的状态默认为已发布，发布时间使用创建时间
+func (repo *MySQLRepository) MigrateLegacyColumns() ([]string, error) {
+	migrator := repo.db.Migrator()
+	var added []string
+	for _, column := range legacyColumns {
+		if migrator.HasColumn(column.table, column.name) {
+			continue
+		}
+		if err := repo.db.Exec(fmt.Sprintf("ALTER TABLE `%s` ADD COLUMN `%s` %s", column.table, column.name, column.definition)).Error; err != nil {
+			return added, fmt.Errorf("adding column %s.%s: %w", column.table, column.name, err)
+		}
+		added = append(added, column.table+"."+column.name)
+
+		if column.table == "article" && column.name == "published_at" {
+			err := repo.db.Model(&models.Article{}).
+				Where("status = ? AND published_at IS NULL", models.ArticleStatusPublished).
+				UpdateColumn("published_at", gorm.Expr("created_at")).Error
+			if err != nil {
+				return added, err
+			}
+		}
+	}
+	return added, nil
+}
+
+// MigrateLegacyIndexes 为旧版本的表补充缺少的索引，需要在所有文章都有访问标识后执行，返回新增的索引名
+func (repo *MySQLRepository) MigrateLegacyIndexes() ([]string, error) {
+	migrator := repo.db.Migrator()
+	var added []string
+	for _, index := range legacyIndexes {
+		if migrator.HasIndex(index.table, index.name) {
+			continue
+		}
+		if err := repo.db.Exec(fmt.Sprintf("ALTER TABLE `%s` ADD %s", index.table, index.definition)).Error; err != nil {
+			return added, fmt.Errorf("adding index %s.%s: %w", index.table, index.name, err)
+		}
+		added = append(added, index.name)
+	}
+
+	// 去掉添加slug列时使用的默认值，与init.sql一致
+	return added, repo.db.Exec("ALTER TABLE `article` ALTER COLUMN `slug` DROP DEFAULT").Error
+}
+
+// LowercaseTagNames 将标签名转为小写，与normalizeTags一致；tag表的唯一索引不区分大小写，不会产生冲突
+func (repo *MySQLRepository) LowercaseTagNames() (int64, error) {
+	result := repo.db.Model(&models.Tag{}).
+		Where("BINARY name <> LOWER(name)").
+		UpdateColumn("name", gorm.Expr("LOWER(name)"))
+	return result.RowsAffected, result.Error
+}
+
+// ListArticlesWithoutSlug 按ID顺序获取afterID之后没有访问标识的文章
+func (repo *MySQLRepository) ListArticlesWithoutSlug(afterID uint64, limit int) ([]models.Article, error) {
+	var articles []models.Article
+	err := repo.db.Select("id", "title").
+		Where("id > ? AND slug = ''", afterID).
+		Order("id").
+		Limit(limit).
+		Find(&articles).Error
+	return articles, err
+}
+
+// UpdateArticleSlug 设置文章的访问标识，不修改更新时间
+func (repo *MySQLRepository) UpdateArticleSlug(articleID uint64, slug string) error {
+	return repo.db.Model(&models.Article{}).Where("id = ?", articleID).UpdateColumn("slug", slug).Error
+}
+
+// ListContentsWithoutHTML 按文章ID顺序获取afterID之后有内容但没有HTML内容的文章内容，以及对应文章的内容格式
+func (repo *MySQLRepository) ListContentsWithoutHTML(afterID uint64, limit int) ([]models.ArticleContent, map[uint64]string, error) {
+	var contents []models.ArticleContent
+	err := repo.db.Where("article_id > ? AND content_html = '' AND content <> ''", afterID).
+		Order("article_id").
+		Limit(limit).
+		Find(&contents).Error
+	if err != nil || len(contents) == 0 {
+		return contents, nil, err
+	}
+
+	ids := make([]uint64, len(contents))
+	for i, content := range contents {
+		ids[i] = content.ArticleID
+	}
+	var articles []models.Article
+	if err := repo.db.Select("id", "content_format").Where("id IN ?", ids).Find(&articles).Error; err != nil {
+		return nil, nil, err
+	}
+	formats := make(map[uint64]string, len(articles))
+	for _, article := range articles {
+		formats[article.ID] = article.ContentFormat
+	}
+	return contents, formats, nil
+}
+
+// UpdateContentHTML 设置文章的HTML内容
+func (repo *MySQLRepository) UpdateContentHTML(articleID uint64, contentHTML string) error {
+	return repo.db.Model(&models.ArticleContent{}).Where("article_id = ?", articleID).UpdateColumn("content_html", contentHTML).Error
+}
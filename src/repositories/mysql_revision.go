@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"demo/src/errs"
+	"demo/src/models"
+	"errors"
+	"gorm.io/gorm"
+)
+
+// addArticleRevision 在事务中追加文章版本，版本号为当前最大版本号加1
+func addArticleRevision(tx *gorm.DB, article *models.Article, articleContent *models.ArticleContent) error {
+	var maxRevision int
+	if err := tx.Model(&models.ArticleRevision{}).
+		Where("article_id = ?", article.ID).
+		Select("COALESCE(MAX(revision), 0)").
+		Scan(&maxRevision).Error; err != nil {
+		return err
+	}
+
+	return tx.Create(&models.ArticleRevision{
+		ArticleID:     article.ID,
+		Revision:      maxRevision + 1,
+		Title:         article.Title,
+		Picture:       article.Picture,
+		ContentFormat: article.ContentFormat,
+		Content:       articleContent.Content,
+	}).Error
+}
+
+// ListArticleRevisions 获取文章版本列表，不包含内容
+func (repo *MySQLRepository) ListArticleRevisions(articleID uint64) ([]models.ArticleRevision, error) {
+	revisions := []models.ArticleRevision{}
+	err := repo.db.Omit("Content").
+		Where("article_id = ?", articleID).
+		Order("revision DESC").
+		Find(&revisions).Error
+	return revisions, err
+}
+
+// GetArticleRevision 获取文章的指定版本
+func (repo *MySQLRepository) GetArticleRevision(articleID uint64, revision int) (*models.ArticleRevision, error) {
+	var articleRevision models.ArticleRevision
+	if err := repo.db.Where("article_id = ? AND revision = ?", articleID, revision).Take(&articleRevision).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errs.ErrRevisionNotFound
+		}
+		return nil, err
+	}
+	return &articleRevision, nil
+}
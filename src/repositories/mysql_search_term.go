@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"demo/src/errs"
+	"demo/src/models"
+	"gorm.io/gorm/clause"
+)
+
+// ListSearchSynonyms 获取全部同义词组
+func (repo *MySQLRepository) ListSearchSynonyms() ([]models.SearchSynonym, error) {
+	var synonyms []models.SearchSynonym
+	err := repo.db.Order("id").Find(&synonyms).Error
+	return synonyms, err
+}
+
+// AddSearchSynonym 新增同义词组
+func (repo *MySQLRepository) AddSearchSynonym(synonym *models.SearchSynonym) error {
+	return repo.db.Create(synonym).Error
+}
+
+// UpdateSearchSynonym 更新同义词组
+func (repo *MySQLRepository) UpdateSearchSynonym(synonym *models.SearchSynonym) error {
+	result := repo.db.Model(&models.SearchSynonym{}).
+		Where("id = ?", synonym.ID).
+		Select("Terms", "UpdatedAt").
+		Updates(models.SearchSynonym{
+			Terms:     synonym.Terms,
+			UpdatedAt: synonym.UpdatedAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errs.ErrSynonymNotFound
+	}
+	return nil
+}
+
+// DeleteSearchSynonym 删除同义词组
+func (repo *MySQLRepository) DeleteSearchSynonym(synonymID uint64) error {
+	result := repo.db.Where("id = ?", synonymID).Delete(&models.SearchSynonym{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errs.ErrSynonymNotFound
+	}
+	return nil
+}
+
+// ListSearchStopwords 获取全部停用词
+func (repo *MySQLRepository) ListSearchStopwords() ([]models.SearchStopword, error) {
+	var stopwords []models.SearchStopword
+	err := repo.db.Order("id").Find(&stopwords).Error
+	return stopwords, err
+}
+
+// AddSearchStopword 新增停用词，停用词已存在时返回错误
+func (repo *MySQLRepository) AddSearchStopword(stopword *models.SearchStopword) error {
+	result := repo.db.Clauses(clause.OnConflict{DoNothing: true}).Create(stopword)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errs.ErrStopwordExists
+	}
+	return nil
+}
+
+// DeleteSearchStopword 删除停用词
+func (repo *MySQLRepository) DeleteSearchStopword(stopwordID uint64) error {
+	result := repo.db.Where("id = ?", stopwordID).Delete(&models.SearchStopword{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errs.ErrStopwordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package repositories
+
+import (
+	"demo/src/errs"
+	"demo/src/models"
+	"errors"
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"strings"
+)
+
+// mysqlDuplicateEntry MySQL唯一索引冲突的错误码
+const mysqlDuplicateEntry = 1062
+
+// slugConflict 将访问标识唯一索引uk_slug的冲突转换为ErrSlugTaken，其他错误原样返回；
+// 并发写入时两篇文章可能同时通过SlugOwner检查，后写入的一方由此得知需要换用其他标识
+func slugConflict(err error) error {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntry && strings.Contains(mysqlErr.Message, "uk_slug") {
+		return errs.ErrSlugTaken
+	}
+	return err
+}
+
+// SlugOwner 查询使用该访问标识的文章ID，包括历史访问标识，未被使用时返回0
+func (repo *MySQLRepository) SlugOwner(slug string) (uint64, error) {
+	var article models.Article
+	err := repo.db.Select("id").Where("slug = ?", slug).Take(&article).Error
+	if err == nil {
+		return article.ID, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, err
+	}
+
+	var redirect models.ArticleSlugRedirect
+	err = repo.db.Where("slug = ?", slug).Take(&redirect).Error
+	if err == nil {
+		return redirect.ArticleID, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, err
+	}
+	return 0, nil
+}
+
+// FindArticleBySlug 根据访问标识查询文章，访问标识是历史值时redirected为true
+func (repo *MySQLRepository) FindArticleBySlug(slug string) (article *models.Article, redirected bool, err error) {
+	var current models.Article
+	err = repo.db.Where("slug = ?", slug).Take(&current).Error
+	if err == nil {
+		return &current, false, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, false, err
+	}
+
+	var redirect models.ArticleSlugRedirect
+	if err = repo.db.Where("slug = ?", slug).Take(&redirect).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, false, errs.ErrArticleNotFound
+		}
+		return nil, false, err
+	}
+
+	article, err = repo.FindArticle(redirect.ArticleID)
+	return article, true, err
+}
+
+// saveSlugRedirect 访问标识变化时在事务中保留旧标识作为跳转
+func saveSlugRedirect(tx *gorm.DB, articleID uint64, oldSlug, newSlug string) error {
+	if oldSlug == newSlug {
+		return nil
+	}
+
+	// 新标识如果是本文章的历史标识，删除对应的跳转记录
+	if err := tx.Where("slug = ? AND article_id = ?", newSlug, articleID).Delete(&models.ArticleSlugRedirect{}).Error; err != nil {
+		return err
+	}
+
+	if oldSlug == "" {
+		return nil
+	}
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "slug"}},
+		DoUpdates: clause.AssignmentColumns([]string{"article_id"}),
+	}).Create(&models.ArticleSlugRedirect{Slug: oldSlug, ArticleID: articleID}).Error
+}
@@ -0,0 +1,33 @@
+package repositories
+
+import (
+	"demo/src/errs"
+	"errors"
+	"fmt"
+	"github.com/go-sql-driver/mysql"
+	"testing"
+)
+
+func TestSlugConflict(t *testing.T) {
+	other := errors.New("connection refused")
+	externalID := &mysql.MySQLError{Number: 1062, Message: "Duplicate entry '42' for key 'article.uk_external_id'"}
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{name: "slug", err: &mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'hello' for key 'article.uk_slug'"}, want: errs.ErrSlugTaken},
+		{name: "wrapped slug", err: fmt.Errorf("insert: %w", &mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'hello' for key 'uk_slug'"}), want: errs.ErrSlugTaken},
+		{name: "external id", err: externalID, want: externalID},
+		{name: "other", err: other, want: other},
+		{name: "nil"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := slugConflict(tt.err); !errors.Is(got, tt.want) {
+				t.Fatalf("slugConflict() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package repositories
+
+import (
+	"demo/src/models"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// UpsertUser 按subject获取用户，不存在时创建，名称变化时更新，返回用户及名称是否变化
+func (repo *MySQLRepository) UpsertUser(subject, name string) (*models.User, bool, error) {
+	// 并发首次访问时只有一个请求能创建成功，其余忽略冲突后重新读取
+	user := models.User{Subject: subject, Name: name}
+	if err := repo.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&user).Error; err != nil {
+		return nil, false, err
+	}
+	if err := repo.db.Where("subject = ?", subject).Take(&user).Error; err != nil {
+		return nil, false, err
+	}
+
+	if user.Name == name {
+		return &user, false, nil
+	}
+
+	user.Name = name
+	user.UpdatedAt = time.Now()
+	err := repo.db.Model(&models.User{}).
+		Where("id = ?", user.ID).
+		Select("Name", "UpdatedAt").
+		Updates(models.User{Name: user.Name, UpdatedAt: user.UpdatedAt}).Error
+	return &user, err == nil, err
+}
+
+// GetUserName 获取用户名称，用户不存在时返回空字符串
+func (repo *MySQLRepository) GetUserName(userID uint64) (string, error) {
+	var names []string
+	err := repo.db.Model(&models.User{}).Where("id = ?", userID).Limit(1).Pluck("name", &names).Error
+	if err != nil || len(names) == 0 {
+		return "", err
+	}
+	return names[0], nil
+}
+
+// FindUserBySubject 按subject获取用户，用户不存在时返回nil
+func (repo *MySQLRepository) FindUserBySubject(subject string) (*models.User, error) {
+	var users []models.User
+	if err := repo.db.Where("subject = ?", subject).Limit(1).Find(&users).Error; err != nil {
+		return nil, err
+	}
+	if len(users) == 0 {
+		return nil, nil
+	}
+	return &users[0], nil
+}
@@ -3,6 +3,8 @@ package repositories
 import (
 	"context"
 	"demo/src/common/redis_keys"
+	"demo/src/dtos"
+	"encoding/json"
 	"fmt"
 	"github.com/go-redis/redis/v8"
 	"log"
@@ -38,6 +40,108 @@ func (repo *RedisRepository) UnlockArticleID(ctx context.Context, articleID uint
 	return err
 }
 
+// renewLeaderScript 仅当持有者仍是自己时续期，避免续期到其他实例持有的锁
+var renewLeaderScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// releaseLeaderScript 仅当持有者是自己时释放
+var releaseLeaderScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// AcquireLeader 竞选或续期后台任务的leader，返回当前实例是否为leader
+func (repo *RedisRepository) AcquireLeader(ctx context.Context, name, instanceID string, ttl time.Duration) (bool, error) {
+	leaderKey := redis_keys.GetLeaderKey(name)
+
+	// 已经是leader时续期
+	renewed, err := renewLeaderScript.Run(ctx, repo.rdb, []string{leaderKey}, instanceID, ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, err
+	}
+	if renewed == 1 {
+		return true, nil
+	}
+
+	// 竞选leader
+	return repo.rdb.SetNX(ctx, leaderKey, instanceID, ttl).Result()
+}
+
+// ReleaseLeader 释放后台任务的leader身份
+func (repo *RedisRepository) ReleaseLeader(ctx context.Context, name, instanceID string) error {
+	return releaseLeaderScript.Run(ctx, repo.rdb, []string{redis_keys.GetLeaderKey(name)}, instanceID).Err()
+}
+
+// GetRelatedArticles 读取相关文章缓存，缓存不存在时返回nil
+func (repo *RedisRepository) GetRelatedArticles(ctx context.Context, articleID uint64) ([]dtos.RelatedArticle, error) {
+	data, err := repo.rdb.Get(ctx, redis_keys.GetRelatedArticlesKey(articleID)).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	articles := []dtos.RelatedArticle{}
+	if err := json.Unmarshal(data, &articles); err != nil {
+		return nil, err
+	}
+	return articles, nil
+}
+
+// SetRelatedArticles 写入相关文章缓存
+func (repo *RedisRepository) SetRelatedArticles(ctx context.Context, articleID uint64, articles []dtos.RelatedArticle, ttl time.Duration) error {
+	data, err := json.Marshal(articles)
+	if err != nil {
+		return err
+	}
+	return repo.rdb.Set(ctx, redis_keys.GetRelatedArticlesKey(articleID), data, ttl).Err()
+}
+
+// DeleteRelatedArticles 删除相关文章缓存
+func (repo *RedisRepository) DeleteRelatedArticles(ctx context.Context, articleID uint64) error {
+	return repo.rdb.Del(ctx, redis_keys.GetRelatedArticlesKey(articleID)).Err()
+}
+
+// TouchAPIKey 记录API Key的最后使用时间
+func (repo *RedisRepository) TouchAPIKey(ctx context.Context, apiKeyID uint64, usedAt time.Time) error {
+	return repo.rdb.HSet(ctx, redis_keys.APIKeyLastUsed, strconv.FormatUint(apiKeyID, 10), usedAt.Unix()).Err()
+}
+
+// GetAPIKeysLastUsed 批量获取API Key的最后使用时间，没有使用记录的不在结果中
+func (repo *RedisRepository) GetAPIKeysLastUsed(ctx context.Context, apiKeyIDs []uint64) (map[uint64]time.Time, error) {
+	lastUsed := make(map[uint64]time.Time)
+	if len(apiKeyIDs) == 0 {
+		return lastUsed, nil
+	}
+
+	fields := make([]string, len(apiKeyIDs))
+	for i, id := range apiKeyIDs {
+		fields[i] = strconv.FormatUint(id, 10)
+	}
+	values, err := repo.rdb.HMGet(ctx, redis_keys.APIKeyLastUsed, fields...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	for i, value := range values {
+		s, ok := value.(string)
+		if !ok {
+			continue
+		}
+		if unix, err := strconv.ParseInt(s, 10, 64); err == nil {
+			lastUsed[apiKeyIDs[i]] = time.Unix(unix, 0)
+		}
+	}
+	return lastUsed, nil
+}
+
 // InitRedis 初始化Redis连接
 func InitRedis() *redis.Client {
 	// 从.env配置文件中获取Redis连接配置
@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	"context"
+	"demo/src/common/redis_keys"
+	"encoding/json"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	"time"
+)
+
+// IdempotencyRecord 幂等请求记录，请求处理完成前Completed为false
+type IdempotencyRecord struct {
+	Fingerprint string `json:"fingerprint"`
+	Completed   bool   `json:"completed"`
+	Status      int    `json:"status,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// inProgressRecord 处理中的幂等请求记录
+func inProgressRecord(fingerprint string) ([]byte, error) {
+	return json.Marshal(IdempotencyRecord{Fingerprint: fingerprint})
+}
+
+// renewIdempotencyScript 仅当记录仍是处理中的记录时续期，避免延长已保存的响应或其他请求的记录
+var renewIdempotencyScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+
+// StartIdempotentRequest 登记幂等请求，返回true表示首次请求；已存在记录时返回false及已有记录（记录恰好过期时为nil）
+func (repo *RedisRepository) StartIdempotentRequest(ctx context.Context, key, fingerprint string, ttl time.Duration) (bool, *IdempotencyRecord, error) {
+	redisKey := redis_keys.GetIdempotencyKey(key)
+	value, err := inProgressRecord(fingerprint)
+	if err != nil {
+		return false, nil, err
+	}
+
+	ok, err := repo.rdb.SetNX(ctx, redisKey, value, ttl).Result()
+	if err != nil || ok {
+		return ok, nil, err
+	}
+
+	data, err := repo.rdb.Get(ctx, redisKey).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return false, nil, nil
+		}
+		return false, nil, err
+	}
+
+	var record IdempotencyRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return false, nil, err
+	}
+	return false, &record, nil
+}
+
+// RenewIdempotentRequest 延长处理中的幂等请求记录的有效期，请求处理时间超过ttl时定期调用
+func (repo *RedisRepository) RenewIdempotentRequest(ctx context.Context, key, fingerprint string, ttl time.Duration) error {
+	value, err := inProgressRecord(fingerprint)
+	if err != nil {
+		return err
+	}
+	return renewIdempotencyScript.Run(ctx, repo.rdb, []string{redis_keys.GetIdempotencyKey(key)}, value, ttl.Milliseconds()).Err()
+}
+
+// SaveIdempotentResponse 保存幂等请求的响应，在ttl内重试时重放
+func (repo *RedisRepository) SaveIdempotentResponse(ctx context.Context, key string, record *IdempotencyRecord, ttl time.Duration) error {
+	value, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return repo.rdb.Set(ctx, redis_keys.GetIdempotencyKey(key), value, ttl).Err()
+}
+
+// DeleteIdempotentRequest 删除幂等请求记录，允许使用同一个Key重试
+func (repo *RedisRepository) DeleteIdempotentRequest(ctx context.Context, key string) error {
+	return repo.rdb.Del(ctx, redis_keys.GetIdempotencyKey(key)).Err()
+}
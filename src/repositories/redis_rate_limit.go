@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"context"
+	"demo/src/common/redis_keys"
+	"github.com/go-redis/redis/v8"
+	"time"
+)
+
+// gcraScript GCRA限流：记录理论到达时间（TAT），请求到达时间早于TAT-突发容量时拒绝。
+// 使用Redis服务器时间，避免多个实例的时钟偏差。
+// 返回 {是否允许, 剩余次数, 需等待的毫秒数, 完全恢复的毫秒数}
+var gcraScript = redis.NewScript(`
+local interval = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local t = redis.call("TIME")
+local now = t[1] * 1000 + math.floor(t[2] / 1000)
+
+local tat = tonumber(redis.call("GET", KEYS[1]))
+if not tat or tat < now then
+	tat = now
+end
+
+local newTat = tat + interval
+local allowAt = newTat - burst * interval
+if now < allowAt then
+	return {0, 0, allowAt - now, tat - now}
+end
+
+redis.call("SET", KEYS[1], newTat, "PX", newTat - now)
+local remaining = math.floor((now - allowAt) / interval)
+return {1, remaining, 0, newTat - now}
+`)
+
+// RateLimitResult 限流判断结果
+type RateLimitResult struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration // 被拒绝时需要等待的时间
+	Reset      time.Duration // 配额完全恢复需要的时间
+}
+
+// AllowRequest 按GCRA算法判断请求是否允许，period内最多limit次，允许突发limit次
+func (repo *RedisRepository) AllowRequest(ctx context.Context, key string, limit int, period time.Duration) (*RateLimitResult, error) {
+	interval := period.Milliseconds() / int64(limit)
+	if interval < 1 {
+		interval = 1
+	}
+
+	values, err := gcraScript.Run(ctx, repo.rdb, []string{redis_keys.GetRateLimitKey(key)}, interval, limit).Int64Slice()
+	if err != nil {
+		return nil, err
+	}
+
+	return &RateLimitResult{
+		Allowed:    values[0] == 1,
+		Remaining:  int(values[1]),
+		RetryAfter: time.Duration(values[2]) * time.Millisecond,
+		Reset:      time.Duration(values[3]) * time.Millisecond,
+	}, nil
+}
@@ -0,0 +1,38 @@
+package repositories
+
+import (
+	"context"
+	"demo/src/common/env"
+	"io"
+	"log"
+)
+
+// FileStorage 文件存储接口，用于保存上传的文章图片
+type FileStorage interface {
+	// Save 保存文件内容到指定的key
+	Save(ctx context.Context, key string, r io.Reader) error
+	// Exists 检查key对应的文件是否存在
+	Exists(ctx context.Context, key string) (bool, error)
+	// URL 返回key对应文件的访问地址
+	URL(key string) string
+	// Key 解析访问地址对应的key，地址不属于当前存储时返回false
+	Key(fileURL string) (string, bool)
+}
+
+// InitStorage 根据配置初始化文件存储
+func InitStorage() FileStorage {
+	driver := env.String("STORAGE_DRIVER", "local")
+
+	switch driver {
+	case "local":
+		storage, err := NewLocalStorage(env.String("STORAGE_LOCAL_DIR", "uploads"), env.String("STORAGE_BASE_URL", "/uploads"))
+		if err != nil {
+			log.Fatalf("Failed to init local storage: %v", err)
+		}
+		return storage
+	default:
+		log.Fatalf("Unsupported storage driver: %s", driver)
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"bytes"
+	"demo/src/common/env"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// stopwordPlaceholder 停用词在同义词文件中替换成的占位词，由检索分析器中的停用词过滤器去除。
+// 只有同义词过滤器支持在线重新加载，停用词借助同义词规则实现
+const stopwordPlaceholder = "__stopword__"
+
+// synonymsFileName 同义词文件名，需要与ES_SYNONYMS_PATH的文件名一致
+const synonymsFileName = "article_synonyms.txt"
+
+// formatSynonymRules 生成Solr格式的同义词规则：每组同义词一行，停用词写成替换为占位词的规则
+func formatSynonymRules(terms SearchTerms) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("# Generated from the search_synonym and search_stopword tables, do not edit.\n")
+	for _, synonym := range terms.Synonyms {
+		buf.WriteString(strings.Join(synonym, ", "))
+		buf.WriteByte('\n')
+	}
+	for _, stopword := range terms.Stopwords {
+		buf.WriteString(stopword)
+		buf.WriteString(" => ")
+		buf.WriteString(stopwordPlaceholder)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
+// WriteSynonymsFile 将同义词和停用词写入ES_ANALYSIS_DIR下的同义词文件，该目录需要挂载到各ES节点的
+// config/analysis目录；先写临时文件再重命名，ES重新加载时不会读到写了一半的文件
+func WriteSynonymsFile(terms SearchTerms) error {
+	dir := env.String("ES_ANALYSIS_DIR", "analysis")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, synonymsFileName+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(formatSynonymRules(terms)); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	// ES以其他用户运行，文件需要可读
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(dir, synonymsFileName))
+}
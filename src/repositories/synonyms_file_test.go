@@ -0,0 +1,52 @@
+package repositories
+
+import (
+	"demo/src/models"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFormatSynonymRules(t *testing.T) {
+	terms := SearchTerms{
+		Synonyms:  []models.StringList{{"手机", "移动电话"}, {"tv", "television", "电视"}},
+		Stopwords: []string{"the", "的"},
+	}
+	want := "# Generated from the search_synonym and search_stopword tables, do not edit.\n" +
+		"手机, 移动电话\n" +
+		"tv, television, 电视\n" +
+		"the => __stopword__\n" +
+		"的 => __stopword__\n"
+	if got := string(formatSynonymRules(terms)); got != want {
+		t.Errorf("formatSynonymRules() = %q, want %q", got, want)
+	}
+}
+
+func TestWriteSynonymsFile(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("ES_ANALYSIS_DIR", dir)
+
+	if err := WriteSynonymsFile(SearchTerms{Stopwords: []string{"a"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteSynonymsFile(SearchTerms{Stopwords: []string{"b"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, synonymsFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := string(formatSynonymRules(SearchTerms{Stopwords: []string{"b"}})); string(data) != want {
+		t.Errorf("file content = %q, want %q", data, want)
+	}
+
+	// 临时文件重命名后不应残留
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected only %s in %s, got %d entries", synonymsFileName, dir, len(entries))
+	}
+}
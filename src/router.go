@@ -1,26 +1,142 @@
 package main
 
 import (
+	"demo/src/common/auth"
+	"demo/src/common/env"
 	"demo/src/handlers"
+	"demo/src/middlewares"
+	"demo/src/repositories"
 	"demo/src/services"
 	"github.com/gin-gonic/gin"
+	"log"
+	"strings"
 )
 
-func SetupRouter(articleService *services.ArticleService) *gin.Engine {
+func SetupRouter(articleService *services.ArticleService, tagService *services.TagService, categoryService *services.CategoryService, uploadService *services.UploadService, searchTermService *services.SearchTermService, apiKeyService *services.APIKeyService, exportService *services.ExportService, storage repositories.FileStorage, verifier *auth.Verifier, redisRepo *repositories.RedisRepository, rateLimitRules *middlewares.RateLimitRules) *gin.Engine {
 	router := gin.Default()
 
+	// 只信任来自反向代理的X-Forwarded-For，用于获取限流使用的客户端IP
+	if err := router.SetTrustedProxies(strings.Split(env.String("TRUSTED_PROXIES", "127.0.0.1,::1,10.0.0.0/8,172.16.0.0/12,192.168.0.0/16"), ",")); err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
+
+	// 本地存储时由服务直接提供上传文件的访问
+	if localStorage, ok := storage.(*repositories.LocalStorage); ok {
+		router.Static(localStorage.BasePath(), localStorage.Root())
+	}
+
 	// api路由组 v1
-	v1 := router.Group("/api/v1")
+	// 写操作需要JWT或API Key认证，只读接口允许匿名访问；认证前按客户端IP限制总次数，认证后按调用方和路由限流，
+	// 写操作可通过refresh参数指定ES刷新策略
+	v1 := router.Group("/api/v1",
+		middlewares.IPRateLimit(redisRepo, rateLimitRules),
+		middlewares.Auth(verifier, apiKeyService),
+		middlewares.RateLimit(redisRepo, rateLimitRules),
+		middlewares.Refresh(),
+	)
 	{
 		articleHandler := handlers.NewArticleHandler(articleService)
-		// 新增文章
-		v1.POST("/article", articleHandler.AddArticle)
+		// 新增文章，支持Idempotency-Key防止重试时重复创建
+		v1.POST("/article", middlewares.Idempotency(redisRepo), articleHandler.AddArticle)
+
+		// 批量新增或更新文章，支持JSON数组和NDJSON
+		v1.POST("/articles/bulk", articleHandler.BulkSaveArticles)
+
+		// 获取文章详情
+		v1.GET("/article/:article_id", articleHandler.GetArticle)
+
+		// 根据访问标识获取文章详情
+		v1.GET("/article/by-slug/:slug", articleHandler.GetArticleBySlug)
+
+		// 获取相关文章
+		v1.GET("/article/:article_id/related", articleHandler.RelatedArticles)
 
 		// 获取文章列表
 		v1.GET("/articles", articleHandler.ListArticles)
 
+		// 文章标题联想
+		v1.GET("/articles/suggest", articleHandler.SuggestArticles)
+
 		// 更新文章
 		v1.PUT("/article/:article_id", articleHandler.UpdateArticle)
+
+		// 删除文章
+		v1.DELETE("/article/:article_id", articleHandler.DeleteArticle)
+
+		// 发布文章，可指定publish_at定时发布
+		v1.POST("/article/:article_id/publish", articleHandler.PublishArticle)
+
+		// 获取文章版本列表
+		v1.GET("/article/:article_id/revisions", articleHandler.ListRevisions)
+
+		// 比较文章的两个版本
+		v1.GET("/article/:article_id/revisions/diff", articleHandler.DiffRevisions)
+
+		// 获取文章版本详情
+		v1.GET("/article/:article_id/revisions/:revision", articleHandler.GetRevision)
+
+		// 恢复文章到指定版本
+		v1.POST("/article/:article_id/revisions/:revision/rollback", articleHandler.RollbackArticle)
+
+		exportHandler := handlers.NewExportHandler(exportService)
+		// 导出文章，支持NDJSON、CSV和JSON，过滤条件与文章列表一致
+		v1.GET("/articles/export", exportHandler.ExportArticles)
+
+		tagHandler := handlers.NewTagHandler(tagService)
+		// 获取标签及文章数量
+		v1.GET("/tags", tagHandler.ListTags)
+
+		categoryHandler := handlers.NewCategoryHandler(categoryService)
+		// 新增分类
+		v1.POST("/category", categoryHandler.AddCategory)
+
+		// 获取分类树
+		v1.GET("/categories", categoryHandler.ListCategories)
+
+		// 获取分类详情
+		v1.GET("/category/:category_id", categoryHandler.GetCategory)
+
+		// 更新分类
+		v1.PUT("/category/:category_id", categoryHandler.UpdateCategory)
+
+		// 删除分类，分类下还有文章或子分类时拒绝删除
+		v1.DELETE("/category/:category_id", categoryHandler.DeleteCategory)
+
+		uploadHandler := handlers.NewUploadHandler(uploadService)
+		// 上传文章图片
+		v1.POST("/uploads", uploadHandler.UploadImage)
+
+		searchTermHandler := handlers.NewSearchTermHandler(searchTermService)
+		// 获取检索同义词组
+		v1.GET("/search/synonyms", searchTermHandler.ListSynonyms)
+
+		// 新增检索同义词组
+		v1.POST("/search/synonym", searchTermHandler.AddSynonym)
+
+		// 更新检索同义词组
+		v1.PUT("/search/synonym/:synonym_id", searchTermHandler.UpdateSynonym)
+
+		// 删除检索同义词组
+		v1.DELETE("/search/synonym/:synonym_id", searchTermHandler.DeleteSynonym)
+
+		// 获取检索停用词
+		v1.GET("/search/stopwords", searchTermHandler.ListStopwords)
+
+		// 新增检索停用词
+		v1.POST("/search/stopword", searchTermHandler.AddStopword)
+
+		// 删除检索停用词
+		v1.DELETE("/search/stopword/:stopword_id", searchTermHandler.DeleteStopword)
+
+		apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyService)
+		// 获取API Key列表
+		v1.GET("/api-keys", apiKeyHandler.ListAPIKeys)
+
+		// 创建API Key
+		v1.POST("/api-key", apiKeyHandler.AddAPIKey)
+
+		// 吊销API Key
+		v1.DELETE("/api-key/:api_key_id", apiKeyHandler.RevokeAPIKey)
 	}
 
 	return router
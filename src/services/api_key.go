@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"demo/src/common/auth"
+	"demo/src/dtos"
+	"demo/src/models"
+	"demo/src/repositories"
+	"encoding/base64"
+	"encoding/hex"
+	"github.com/go-redis/redis/v8"
+	"gorm.io/gorm"
+	"log"
+	"time"
+)
+
+const (
+	apiKeyPrefix       = "dk_" // API Key前缀，便于在日志和代码中识别
+	apiKeyRandomBytes  = 32    // 随机部分的字节数
+	apiKeyDisplayChars = 10    // 保存用于识别的前几位字符数
+)
+
+// APIKeyService 管理机器调用方使用的API Key
+type APIKeyService struct {
+	mysqlRepo   *repositories.MySQLRepository
+	redisRepo   *repositories.RedisRepository
+	userService *UserService
+	policy      *auth.Policy
+}
+
+func NewAPIKeyService(db *gorm.DB, rdb *redis.Client, userService *UserService, policy *auth.Policy) *APIKeyService {
+	return &APIKeyService{
+		mysqlRepo:   repositories.NewMySQLRepository(db),
+		redisRepo:   repositories.NewRedisRepository(rdb),
+		userService: userService,
+		policy:      policy,
+	}
+}
+
+// hashAPIKey 计算密钥的SHA-256哈希，密钥是高熵随机值，不需要加盐
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// AddAPIKey 创建API Key，完整密钥只在创建时返回
+func (s *APIKeyService) AddAPIKey(ctx context.Context, req *dtos.APIKeyAddRequest) (*dtos.APIKeyAddResultData, error) {
+	if err := authorize(ctx, s.policy, auth.PermManageAPIKeys); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userService.CurrentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	random := make([]byte, apiKeyRandomBytes)
+	if _, err := rand.Read(random); err != nil {
+		return nil, err
+	}
+	key := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(random)
+
+	apiKey := models.APIKey{
+		Name:      req.Name,
+		Prefix:    key[:apiKeyDisplayChars],
+		KeyHash:   hashAPIKey(key),
+		Scopes:    splitCSVList(req.Scopes),
+		CreatedBy: user.ID,
+	}
+	if err := s.mysqlRepo.AddAPIKey(&apiKey); err != nil {
+		return nil, err
+	}
+
+	return &dtos.APIKeyAddResultData{APIKey: apiKey, Key: key}, nil
+}
+
+// ListAPIKeys 获取全部API Key及最后使用时间
+func (s *APIKeyService) ListAPIKeys(ctx context.Context) ([]models.APIKey, error) {
+	if err := authorize(ctx, s.policy, auth.PermManageAPIKeys); err != nil {
+		return nil, err
+	}
+
+	apiKeys, err := s.mysqlRepo.ListAPIKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint64, len(apiKeys))
+	for i, apiKey := range apiKeys {
+		ids[i] = apiKey.ID
+	}
+	lastUsed, err := s.redisRepo.GetAPIKeysLastUsed(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range apiKeys {
+		if usedAt, ok := lastUsed[apiKeys[i].ID]; ok {
+			apiKeys[i].LastUsedAt = &usedAt
+		}
+	}
+
+	return apiKeys, nil
+}
+
+// RevokeAPIKey 吊销API Key
+func (s *APIKeyService) RevokeAPIKey(ctx context.Context, apiKeyID uint64) error {
+	if err := authorize(ctx, s.policy, auth.PermManageAPIKeys); err != nil {
+		return err
+	}
+	return s.mysqlRepo.RevokeAPIKey(apiKeyID)
+}
+
+// VerifyAPIKey 校验API Key并返回调用方，调用方只拥有API Key的权限范围，没有角色
+func (s *APIKeyService) VerifyAPIKey(ctx context.Context, key string) (*auth.Principal, error) {
+	apiKey, err := s.mysqlRepo.FindActiveAPIKey(hashAPIKey(key))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.redisRepo.TouchAPIKey(ctx, apiKey.ID, time.Now()); err != nil {
+		log.Printf("Failed to record api key usage! ID: %d, Error: %v", apiKey.ID, err)
+	}
+
+	return &auth.Principal{
+		Subject: auth.APIKeySubject(apiKey.ID),
+		Name:    apiKey.Name,
+		Scopes:  apiKey.Scopes,
+	}, nil
+}
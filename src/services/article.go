@@ -2,31 +2,54 @@ package services
 
 import (
 	"context"
+	"demo/src/common/auth"
+	"demo/src/common/env"
+	"demo/src/common/markup"
 	"demo/src/dtos"
 	"demo/src/errs"
 	"demo/src/models"
 	"demo/src/repositories"
 	"errors"
+	"fmt"
 	"github.com/elastic/go-elasticsearch/v8"
 	"github.com/go-redis/redis/v8"
 	"gorm.io/gorm"
 	"log"
-	"sync"
+	"strings"
+	"time"
 )
 
 const maxSummaryLength = 200 // 文章摘要字符
 
+const defaultSuggestSize = 10 // 默认返回的联想数量
+
 type ArticleService struct {
 	mysqlRepo         *repositories.MySQLRepository
 	elasticsearchRepo *repositories.ElasticsearchRepository
 	redisRepo         *repositories.RedisRepository
+	uploadService     *UploadService
+	categoryService   *CategoryService
+	userService       *UserService
+	policy            *auth.Policy
+	relatedCacheTTL   time.Duration
+	bulkBatchSize     int
+	bulkMaxItems      int
+	bulkMaxBodySize   int64
 }
 
-func NewArticleService(db *gorm.DB, esClient *elasticsearch.Client, rdb *redis.Client) *ArticleService {
+func NewArticleService(db *gorm.DB, esClient *elasticsearch.Client, rdb *redis.Client, uploadService *UploadService, categoryService *CategoryService, userService *UserService, policy *auth.Policy) *ArticleService {
 	return &ArticleService{
 		mysqlRepo:         repositories.NewMySQLRepository(db),
 		elasticsearchRepo: repositories.NewElasticsearchRepository(esClient),
 		redisRepo:         repositories.NewRedisRepository(rdb),
+		uploadService:     uploadService,
+		categoryService:   categoryService,
+		userService:       userService,
+		policy:            policy,
+		relatedCacheTTL:   env.Duration("RELATED_CACHE_TTL", 10*time.Minute),
+		bulkBatchSize:     env.Int("BULK_BATCH_SIZE", 100),
+		bulkMaxItems:      env.Int("BULK_MAX_ITEMS", 1000),
+		bulkMaxBodySize:   env.Int64("BULK_MAX_BODY_SIZE", 32<<20),
 	}
 }
 
@@ -38,6 +61,118 @@ func generateSummary(content string) string {
 	return content[:maxSummaryLength]
 }
 
+// splitCSVList 拆分逗号分隔或重复传入的参数值，去除首尾空格、空值和重复项
+func splitCSVList(values []string) []string {
+	items := []string{}
+	seen := make(map[string]bool)
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			item = strings.TrimSpace(item)
+			if item == "" || seen[item] {
+				continue
+			}
+			seen[item] = true
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// normalizeTags 规范化标签：拆分逗号分隔的值，去除首尾空格和空值，转为小写后去除重复项；
+// MySQL的tag表按不区分大小写的排序规则去重，ES中同样只保存小写的标签
+func normalizeTags(tags []string) []string {
+	lowered := make([]string, len(tags))
+	for i, tag := range tags {
+		lowered[i] = strings.ToLower(tag)
+	}
+	return splitCSVList(lowered)
+}
+
+// resolveStatus 根据请求的状态和定时发布时间计算文章状态和发布时间，current为更新前的文章（新增时为nil）
+func resolveStatus(status string, publishAt *time.Time, current *models.Article) (string, *time.Time, error) {
+	var publishedAt *time.Time
+	if current != nil {
+		publishedAt = current.PublishedAt
+		if status == "" {
+			return current.Status, publishedAt, nil
+		}
+	}
+
+	now := time.Now()
+	switch status {
+	case "", models.ArticleStatusPublished:
+		// 保留已发布文章的原始发布时间
+		if current == nil || current.Status != models.ArticleStatusPublished || publishedAt == nil {
+			publishedAt = &now
+		}
+		return models.ArticleStatusPublished, publishedAt, nil
+	case models.ArticleStatusScheduled:
+		if publishAt == nil || !publishAt.After(now) {
+			return "", nil, errs.ErrInvalidPublishTime
+		}
+		return models.ArticleStatusScheduled, publishAt, nil
+	default:
+		// 草稿和归档保留原发布时间
+		return status, publishedAt, nil
+	}
+}
+
+// renderContent 渲染文章内容，返回存储用的原始内容、HTML内容和摘要
+func renderContent(format, content string) (source, contentHTML, summary string, err error) {
+	format = markup.NormalizeFormat(format)
+	if format == markup.FormatHTML {
+		// HTML原始内容同样需要过滤危险标签和属性后再存储
+		content = markup.Sanitize(content)
+	}
+
+	contentHTML, err = markup.Render(format, content)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	return content, contentHTML, generateSummary(contentText(format, content, contentHTML)), nil
+}
+
+// contentText 获取正文纯文本，用于生成摘要和全文检索
+func contentText(format, source, contentHTML string) string {
+	if markup.NormalizeFormat(format) == markup.FormatPlain {
+		return source
+	}
+	return markup.PlainText(contentHTML)
+}
+
+// checkEditable 检查当前用户能否修改文章：拥有edit_any权限，或是作者本人且拥有edit_own权限
+func (s *ArticleService) checkEditable(ctx context.Context, article *models.Article) error {
+	principal := auth.FromContext(ctx)
+	if principal == nil {
+		return errs.ErrUnauthenticated
+	}
+	if s.policy.Allowed(principal, auth.PermEditAny) {
+		return nil
+	}
+
+	user, err := s.userService.CurrentUser(ctx)
+	if err != nil {
+		return err
+	}
+	if article.AuthorID == user.ID {
+		return authorize(ctx, s.policy, auth.PermEditOwn)
+	}
+	return errs.NewPermissionError(auth.PermEditAny)
+}
+
+// checkReadable 检查当前用户能否查看文章：已发布的文章所有人可见，草稿、定时发布和已归档的文章只有能修改的用户可见，
+// 其他用户按文章不存在处理，不暴露文章是否存在
+func (s *ArticleService) checkReadable(ctx context.Context, article *models.Article) error {
+	if article.Status == models.ArticleStatusPublished {
+		return nil
+	}
+	if auth.FromContext(ctx) == nil || s.checkEditable(ctx, article) != nil {
+		return errs.ErrArticleNotFound
+	}
+	return nil
+}
+
 // TryLockArticle 获取文章锁
 func (s *ArticleService) TryLockArticle(ctx context.Context, articleID uint64) (bool, error) {
 	locked, err := s.redisRepo.LockArticleID(ctx, articleID)
@@ -55,27 +190,91 @@ func (s *ArticleService) UnlockArticle(ctx context.Context, articleID uint64) er
 
 // AddArticle 新增文章
 func (s *ArticleService) AddArticle(ctx context.Context, articleReq *dtos.ArticleAddRequest) (articleID uint64, err error) {
+	if err := authorize(ctx, s.policy, auth.PermCreate); err != nil {
+		return articleID, err
+	}
+
+	// 当前用户作为作者
+	author, err := s.userService.CurrentUser(ctx)
+	if err != nil {
+		return articleID, err
+	}
+
+	// 渲染文章内容
+	content, contentHTML, summary, err := renderContent(articleReq.ContentFormat, articleReq.Content)
+	if err != nil {
+		return articleID, err
+	}
+
+	// 查找图片的缩略图及宽度版本
+	pictureVariants, err := s.uploadService.PictureVariants(ctx, articleReq.Picture)
+	if err != nil {
+		return articleID, err
+	}
+
+	// 获取分类路径，同时校验分类是否存在
+	categoryPath, err := s.categoryService.CategoryPath(ctx, articleReq.CategoryID)
+	if err != nil {
+		return articleID, err
+	}
+
+	// 计算文章状态，默认立即发布，没有发布权限时默认保存为草稿
+	requestedStatus := articleReq.Status
+	if requestedStatus == "" && !s.policy.Allowed(auth.FromContext(ctx), auth.PermPublish) {
+		requestedStatus = models.ArticleStatusDraft
+	}
+	status, publishedAt, err := resolveStatus(requestedStatus, articleReq.PublishAt, nil)
+	if err != nil {
+		return articleID, err
+	}
+	if isPublishing(status, "") {
+		if err := authorize(ctx, s.policy, auth.PermPublish); err != nil {
+			return articleID, err
+		}
+	}
+
 	// 创建models.Article实例
 	article := models.Article{
-		Title:   articleReq.Title,
-		Picture: articleReq.Picture,
-		Summary: generateSummary(articleReq.Content),
+		Title:           articleReq.Title,
+		Picture:         articleReq.Picture,
+		PictureVariants: pictureVariants,
+		Summary:         summary,
+		ContentFormat:   markup.NormalizeFormat(articleReq.ContentFormat),
+		Tags:            normalizeTags(articleReq.Tags),
+		CategoryID:      articleReq.CategoryID,
+		CategoryPath:    categoryPath,
+		AuthorID:        author.ID,
+		AuthorName:      author.Name,
+		Status:          status,
+		PublishedAt:     publishedAt,
 		//CreatedAt: time.Now(),
 		//UpdatedAt: time.Now(),
 	}
 
 	// 创建models.ArticleContent实例
 	articleContent := models.ArticleContent{
-		Content: articleReq.Content,
+		Content:     content,
+		ContentHTML: contentHTML,
 	}
 
-	// 新增DB文章内容
-	if err = s.mysqlRepo.AddArticle(&article, &articleContent); err != nil {
+	// 生成访问标识并新增DB文章内容，标识被并发新增的文章抢先使用时换用下一个序号
+	reserved := make(map[string]bool)
+	for {
+		if article.Slug, err = s.resolveSlug(0, articleReq.Slug, articleReq.Title, reserved); err != nil {
+			return articleID, err
+		}
+		err = s.mysqlRepo.AddArticle(&article, &articleContent)
+		if !errors.Is(err, errs.ErrSlugTaken) {
+			break
+		}
+		reserved[article.Slug] = true
+	}
+	if err != nil {
 		return articleID, err
 	}
 
 	// 新增ES文章内容
-	if err = s.elasticsearchRepo.AddArticle(ctx, &article); err != nil {
+	if err = s.elasticsearchRepo.AddArticle(ctx, &article, contentText(article.ContentFormat, content, contentHTML)); err != nil {
 		/*
 			TODO:
 			这里需要一个更复杂的错误处理机制，如果先提交事务，这里索引增加失败时就不能回滚事务，数据就会出现仅存在数据库中；
@@ -88,9 +287,83 @@ func (s *ArticleService) AddArticle(ctx context.Context, articleReq *dtos.Articl
 	return article.ID, nil
 }
 
+// GetArticle 获取文章详情
+func (s *ArticleService) GetArticle(ctx context.Context, articleID uint64) (*dtos.ArticleDetailData, error) {
+	article, articleContent, err := s.mysqlRepo.GetArticle(articleID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkReadable(ctx, article); err != nil {
+		return nil, err
+	}
+
+	return &dtos.ArticleDetailData{
+		Article:     *article,
+		Content:     articleContent.Content,
+		ContentHTML: articleContent.ContentHTML,
+	}, nil
+}
+
 // ListArticles 获取文章列表
-func (s *ArticleService) ListArticles(ctx context.Context, page, pageSize int, sortField, sortOrder string) (*dtos.ArticleListResponse, error) {
-	return s.elasticsearchRepo.ListArticles(ctx, page, pageSize, sortField, sortOrder)
+func (s *ArticleService) ListArticles(ctx context.Context, req *dtos.ArticleListRequest) (*dtos.ArticleListResponse, error) {
+	req.Tags = normalizeTags(req.Tags)
+	req.Fields = splitCSVList(req.Fields)
+	req.Facets = splitCSVList(req.Facets)
+	if err := s.authorizeStatusFilter(ctx, &req.ArticleFilter); err != nil {
+		return nil, err
+	}
+	return s.elasticsearchRepo.ListArticles(ctx, req)
+}
+
+// normalizeStatusFilter 拆分status过滤条件并检查是否为有效的文章状态
+func normalizeStatusFilter(values []string) ([]string, error) {
+	statuses := splitCSVList(values)
+	for _, status := range statuses {
+		switch status {
+		case models.ArticleStatusDraft, models.ArticleStatusScheduled, models.ArticleStatusPublished, models.ArticleStatusArchived:
+		default:
+			return nil, fmt.Errorf("%w: %s", errs.ErrInvalidStatusFilter, status)
+		}
+	}
+	return statuses, nil
+}
+
+// authorizeStatusFilter 检查status过滤条件：拥有edit_any权限时可按任意状态查看全部文章，
+// 只有edit_own权限时只能查看自己的文章；未传status时只返回已发布的文章，不需要权限
+func (s *ArticleService) authorizeStatusFilter(ctx context.Context, filter *dtos.ArticleFilter) error {
+	var err error
+	if filter.Status, err = normalizeStatusFilter(filter.Status); err != nil || len(filter.Status) == 0 {
+		return err
+	}
+
+	principal := auth.FromContext(ctx)
+	if principal == nil {
+		return errs.ErrUnauthenticated
+	}
+	if s.policy.Allowed(principal, auth.PermEditAny) {
+		return nil
+	}
+	if err := authorize(ctx, s.policy, auth.PermEditOwn); err != nil {
+		return err
+	}
+
+	user, err := s.userService.CurrentUser(ctx)
+	if err != nil {
+		return err
+	}
+	if filter.AuthorID != 0 && filter.AuthorID != user.ID {
+		return errs.NewPermissionError(auth.PermEditAny)
+	}
+	filter.AuthorID = user.ID
+	return nil
+}
+
+// SuggestArticles 根据标题前缀联想文章
+func (s *ArticleService) SuggestArticles(ctx context.Context, prefix string, size int) ([]dtos.ArticleSuggestion, error) {
+	if size == 0 {
+		size = defaultSuggestSize
+	}
+	return s.elasticsearchRepo.SuggestArticles(ctx, strings.TrimSpace(prefix), size)
 }
 
 // UpdateArticle 更新文章
@@ -112,76 +385,199 @@ func (s *ArticleService) UpdateArticle(ctx context.Context, articleID uint64, ar
 	}()
 
 	// 验证文章是否存在
-	exists, err := s.mysqlRepo.ArticleExists(articleID)
+	current, err := s.mysqlRepo.FindArticle(articleID)
 	if err != nil {
 		return err
 	}
 
-	if !exists {
-		return errors.New("article not found")
+	// 检查修改权限
+	if err := s.checkEditable(ctx, current); err != nil {
+		return err
+	}
+
+	// 渲染文章内容
+	content, contentHTML, summary, err := renderContent(articleReq.ContentFormat, articleReq.Content)
+	if err != nil {
+		return err
+	}
+
+	// 查找图片的缩略图及宽度版本
+	pictureVariants, err := s.uploadService.PictureVariants(ctx, articleReq.Picture)
+	if err != nil {
+		return err
+	}
+
+	// 获取分类路径，同时校验分类是否存在
+	categoryPath, err := s.categoryService.CategoryPath(ctx, articleReq.CategoryID)
+	if err != nil {
+		return err
+	}
+
+	// 计算文章状态，未指定时保持不变
+	status, publishedAt, err := resolveStatus(articleReq.Status, articleReq.PublishAt, current)
+	if err != nil {
+		return err
+	}
+	if isPublishing(status, current.Status) {
+		if err := authorize(ctx, s.policy, auth.PermPublish); err != nil {
+			return err
+		}
 	}
 
 	// 创建models.Article实例
 	article := models.Article{
-		ID:      articleID,
-		Title:   articleReq.Title,
-		Picture: articleReq.Picture,
-		Summary: generateSummary(articleReq.Content),
-		//UpdatedAt: time.Now(),
+		ID:              articleID,
+		Title:           articleReq.Title,
+		Slug:            current.Slug,
+		Picture:         articleReq.Picture,
+		PictureVariants: pictureVariants,
+		Summary:         summary,
+		ContentFormat:   markup.NormalizeFormat(articleReq.ContentFormat),
+		Tags:            normalizeTags(articleReq.Tags),
+		CategoryID:      articleReq.CategoryID,
+		CategoryPath:    categoryPath,
+		Status:          status,
+		PublishedAt:     publishedAt,
+		UpdatedAt:       time.Now(), // 同步写入ES，保证按更新时间过滤和排序可用
 	}
 
 	// 创建models.ArticleContent实例
 	articleContent := models.ArticleContent{
-		ArticleID: articleID,
-		Content:   articleReq.Content,
+		ArticleID:   articleID,
+		Content:     content,
+		ContentHTML: contentHTML,
 	}
 
-	// 使用WaitGroup等待两个异步更新操作
-	var wg sync.WaitGroup
-	wg.Add(2)
+	// 指定了新访问标识或标题变化时重新生成访问标识，旧标识保留为跳转；
+	// 新标识被并发写入的文章抢先使用时换用下一个序号重新写入
+	regenerateSlug := articleReq.Slug != "" || articleReq.Title != current.Title || current.Slug == ""
+	reserved := make(map[string]bool)
+	for {
+		if regenerateSlug {
+			if article.Slug, err = s.resolveSlug(articleID, articleReq.Slug, articleReq.Title, reserved); err != nil {
+				return err
+			}
+		}
+		err = s.saveUpdatedArticle(ctx, &article, &articleContent)
+		if !regenerateSlug || !errors.Is(err, errs.ErrSlugTaken) {
+			break
+		}
+		reserved[article.Slug] = true
+	}
+	if err != nil {
+		return err
+	}
+
+	// 内容或标签变化后相关文章需要重新计算
+	if err := s.redisRepo.DeleteRelatedArticles(ctx, articleID); err != nil {
+		log.Printf("Failed to delete related articles cache! ID: %d, Error: %v", articleID, err)
+	}
 
-	// 错误通道用于从goroutines接收错误
-	errChan := make(chan errs.UpdateError, 2)
+	return nil
+}
 
+// saveUpdatedArticle 先在事务中更新DB中的文章，提交成功后再更新ES；DB更新失败时ES保持原状态，
+// 避免草稿或归档文章因DB回滚而以新状态出现在公开列表中
+func (s *ArticleService) saveUpdatedArticle(ctx context.Context, article *models.Article, articleContent *models.ArticleContent) error {
 	// 更新DB文章内容
-	go func() {
-		defer wg.Done()
-		if err := s.mysqlRepo.UpdateArticle(&article, &articleContent); err != nil {
-			errChan <- errs.NewUpdateError(errs.DB, err)
-		}
-	}()
+	if err := s.mysqlRepo.UpdateArticle(article, articleContent); err != nil {
+		log.Printf("An error occurred while updating the article! ID: %d, Source: %s, Error: %v", article.ID, errs.DB, err)
+		return err
+	}
 
 	// 更新ES文章内容
-	go func() {
-		defer wg.Done()
-		if err := s.elasticsearchRepo.UpdateArticle(ctx, &article); err != nil {
-			errChan <- errs.NewUpdateError(errs.ES, err)
+	if err := s.elasticsearchRepo.UpdateArticle(ctx, article, contentText(article.ContentFormat, articleContent.Content, articleContent.ContentHTML)); err != nil {
+		/*
+			TODO:
+			DB已提交而ES更新失败时两者不一致，需要重试或通过migrate-index -force从DB重建索引
+		*/
+		log.Printf("An error occurred while updating the article! ID: %d, Source: %s, Error: %v", article.ID, errs.ES, err)
+		return err
+	}
+	return nil
+}
+
+// PublishArticle 发布文章，publishAt晚于当前时间时设为定时发布
+func (s *ArticleService) PublishArticle(ctx context.Context, articleID uint64, publishAt *time.Time) error {
+	// 锁定文章
+	locked, err := s.TryLockArticle(ctx, articleID)
+	if err != nil {
+		return err
+	}
+	if !locked {
+		return errors.New("article update in progress, please try again later")
+	}
+
+	// 发布完成后解锁文章
+	defer func() {
+		if err := s.UnlockArticle(ctx, articleID); err != nil {
+			log.Printf("Failed to unlock article with ID %d: %v", articleID, err)
 		}
 	}()
 
-	// 等待所有goroutine完成
-	wg.Wait()
+	current, err := s.mysqlRepo.FindArticle(articleID)
+	if err != nil {
+		return err
+	}
+
+	// 发布需要修改权限和发布权限
+	if err := s.checkEditable(ctx, current); err != nil {
+		return err
+	}
+	if err := authorize(ctx, s.policy, auth.PermPublish); err != nil {
+		return err
+	}
 
-	// 关闭错误通道
-	close(errChan)
+	status := models.ArticleStatusPublished
+	if publishAt != nil && publishAt.After(time.Now()) {
+		status = models.ArticleStatusScheduled
+	}
 
-	// 检查错误通道
-	var updateErr error
-	for e := range errChan {
-		if e.Err != nil {
-			if e.Source == errs.DB {
-				// 处理数据库更新错误
-			} else if e.Source == errs.ES {
-				// 处理ES更新错误
-			}
-			log.Printf("An error occurred while updating the article! ID: %d, Source: %s, Error: %v", articleID, e.Source, e.Err)
-			updateErr = e.Err
-		}
+	status, publishedAt, err := resolveStatus(status, publishAt, current)
+	if err != nil {
+		return err
+	}
+
+	if err := s.mysqlRepo.UpdateArticleStatus(articleID, status, publishedAt); err != nil {
+		return err
 	}
 
-	if updateErr != nil {
-		return updateErr
+	return s.elasticsearchRepo.UpdateArticleStatus(ctx, articleID, status, publishedAt)
+}
+
+// DeleteArticle 删除文章
+func (s *ArticleService) DeleteArticle(ctx context.Context, articleID uint64) error {
+	if err := authorize(ctx, s.policy, auth.PermDelete); err != nil {
+		return err
+	}
+
+	// 锁定文章，避免与更新同时进行
+	locked, err := s.TryLockArticle(ctx, articleID)
+	if err != nil {
+		return err
+	}
+	if !locked {
+		return errors.New("article update in progress, please try again later")
+	}
+	defer func() {
+		if err := s.UnlockArticle(ctx, articleID); err != nil {
+			log.Printf("Failed to unlock article with ID %d: %v", articleID, err)
+		}
+	}()
+
+	// 先删除ES文档再删除数据库记录，任一步失败时数据库记录仍在，重试时ES文档不存在不报错，可以继续删除
+	if _, err := s.mysqlRepo.FindArticle(articleID); err != nil {
+		return err
+	}
+	if err := s.elasticsearchRepo.DeleteArticle(ctx, articleID); err != nil {
+		return err
+	}
+	if err := s.mysqlRepo.DeleteArticle(articleID); err != nil {
+		return err
 	}
 
+	if err := s.redisRepo.DeleteRelatedArticles(ctx, articleID); err != nil {
+		log.Printf("Failed to delete related articles cache! ID: %d, Error: %v", articleID, err)
+	}
 	return nil
 }
@@ -0,0 +1,327 @@
+package services
+
+import (
+	"context"
+	"demo/src/common/auth"
+	"demo/src/common/markup"
+	"demo/src/dtos"
+	"demo/src/errs"
+	"demo/src/models"
+	"demo/src/repositories"
+	"errors"
+	"log"
+	"time"
+)
+
+// ArticleBulkOutcome 批量写入中单篇文章的处理结果，Err不为空时表示失败
+type ArticleBulkOutcome struct {
+	ArticleID uint64
+	Created   bool
+	Err       error
+}
+
+// bulkArticle 准备写入的一篇文章
+type bulkArticle struct {
+	position       int // 在批次中的位置
+	article        *models.Article
+	articleContent *models.ArticleContent
+	text           string // 用于全文检索的正文纯文本
+	slugResolved   bool   // 访问标识是否重新生成，写入时被其他文章抢先使用可以换用下一个序号
+}
+
+// BulkMaxItems 一次批量写入的文章数量上限
+func (s *ArticleService) BulkMaxItems() int {
+	return s.bulkMaxItems
+}
+
+// BulkMaxBodySize 批量写入请求体大小上限（字节）
+func (s *ArticleService) BulkMaxBodySize() int64 {
+	return s.bulkMaxBodySize
+}
+
+// BulkSaveArticles 批量新增或更新文章：按批次在一个事务中写入数据库，每批使用一次ES _bulk请求，全部写入后刷新一次索引；
+// 单篇文章的失败记录在对应的结果中，不影响其他文章
+func (s *ArticleService) BulkSaveArticles(ctx context.Context, items []dtos.ArticleBulkItem) ([]ArticleBulkOutcome, error) {
+	if len(items) == 0 {
+		return nil, errs.ErrEmptyBulk
+	}
+	if len(items) > s.bulkMaxItems {
+		return nil, errs.ErrTooManyBulkItems
+	}
+
+	// 当前用户作为新增文章的作者
+	author, err := s.userService.CurrentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	batchSize := s.bulkBatchSize
+	if batchSize < 1 {
+		batchSize = 1
+	}
+
+	outcomes := make([]ArticleBulkOutcome, len(items))
+	reserved := make(map[string]bool) // 本次请求中已分配的访问标识
+	for start := 0; start < len(items); start += batchSize {
+		end := start + batchSize
+		if end > len(items) {
+			end = len(items)
+		}
+		s.bulkSaveBatch(ctx, items[start:end], outcomes[start:end], author, reserved)
+	}
+
+	// 整个请求只刷新一次索引
+	s.RefreshArticles(ctx)
+
+	return outcomes, nil
+}
+
+// RefreshArticles 批量写入后按刷新策略刷新一次文章索引，刷新失败不影响已写入的文章
+func (s *ArticleService) RefreshArticles(ctx context.Context) {
+	if err := s.elasticsearchRepo.RefreshArticles(ctx); err != nil {
+		log.Printf("Failed to refresh article index after bulk save: %v", err)
+	}
+}
+
+// bulkSaveBatch 写入一个批次的文章，结果写入outcomes的对应位置
+func (s *ArticleService) bulkSaveBatch(ctx context.Context, items []dtos.ArticleBulkItem, outcomes []ArticleBulkOutcome, author *models.User, reserved map[string]bool) {
+	// 更新的文章在写入ES后才解锁
+	var lockedIDs []uint64
+	defer func() {
+		for _, articleID := range lockedIDs {
+			if err := s.UnlockArticle(ctx, articleID); err != nil {
+				log.Printf("Failed to unlock article with ID %d: %v", articleID, err)
+			}
+		}
+	}()
+
+	var prepared []bulkArticle
+	for i := range items {
+		item := &items[i]
+		outcomes[i].ArticleID = item.ArticleID
+		outcomes[i].Created = item.ArticleID == 0
+
+		if item.ArticleID > 0 {
+			locked, err := s.TryLockArticle(ctx, item.ArticleID)
+			if err != nil {
+				outcomes[i].Err = err
+				continue
+			}
+			if !locked {
+				outcomes[i].Err = errors.New("article update in progress, please try again later")
+				continue
+			}
+			lockedIDs = append(lockedIDs, item.ArticleID)
+		}
+
+		article, err := s.prepareBulkArticle(ctx, item, author, reserved)
+		if err != nil {
+			outcomes[i].Err = err
+			continue
+		}
+		article.position = i
+		prepared = append(prepared, article)
+	}
+	if len(prepared) == 0 {
+		return
+	}
+
+	// 写入数据库
+	itemErrs, err := s.saveBulkArticles(prepared, items, reserved)
+	if err != nil {
+		for _, article := range prepared {
+			outcomes[article.position].Err = err
+		}
+		return
+	}
+
+	// 写入ES
+	var saved []bulkArticle
+	var docs []repositories.ArticleBulkDocument
+	for i, article := range prepared {
+		if itemErrs[i] != nil {
+			outcomes[article.position].Err = itemErrs[i]
+			continue
+		}
+		outcomes[article.position].ArticleID = article.article.ID
+		saved = append(saved, article)
+		docs = append(docs, repositories.ArticleBulkDocument{
+			Article: article.article,
+			Content: article.text,
+			Created: outcomes[article.position].Created,
+		})
+	}
+	if len(docs) == 0 {
+		return
+	}
+
+	itemErrs, err = s.elasticsearchRepo.BulkSaveArticles(ctx, docs)
+	for i, article := range saved {
+		if err != nil {
+			outcomes[article.position].Err = err
+		} else if itemErrs[i] != nil {
+			outcomes[article.position].Err = itemErrs[i]
+		}
+
+		// 内容或标签变化后相关文章需要重新计算
+		if !outcomes[article.position].Created {
+			if err := s.redisRepo.DeleteRelatedArticles(ctx, article.article.ID); err != nil {
+				log.Printf("Failed to delete related articles cache! ID: %d, Error: %v", article.article.ID, err)
+			}
+		}
+	}
+}
+
+// saveBulkArticles 将准备好的文章写入数据库，重新生成的访问标识被并发写入的文章抢先使用时，
+// 对这些文章换用下一个序号再写入一次，直到成功或没有可用的标识
+func (s *ArticleService) saveBulkArticles(prepared []bulkArticle, items []dtos.ArticleBulkItem, reserved map[string]bool) ([]error, error) {
+	itemErrs := make([]error, len(prepared))
+	pending := make([]int, len(prepared))
+	for i := range pending {
+		pending[i] = i
+	}
+
+	for len(pending) > 0 {
+		writes := make([]repositories.ArticleWrite, len(pending))
+		for j, i := range pending {
+			writes[j] = repositories.ArticleWrite{Article: prepared[i].article, ArticleContent: prepared[i].articleContent}
+		}
+		writeErrs, err := s.mysqlRepo.SaveArticles(writes)
+		if err != nil {
+			return nil, err
+		}
+
+		var retry []int
+		for j, i := range pending {
+			itemErrs[i] = writeErrs[j]
+			if !prepared[i].slugResolved || !errors.Is(writeErrs[j], errs.ErrSlugTaken) {
+				continue
+			}
+			item := &items[prepared[i].position]
+			articleSlug, err := s.resolveSlug(item.ArticleID, item.Slug, item.Title, reserved)
+			if err != nil {
+				itemErrs[i] = err
+				continue
+			}
+			reserved[articleSlug] = true
+			prepared[i].article.Slug = articleSlug
+			retry = append(retry, i)
+		}
+		pending = retry
+	}
+	return itemErrs, nil
+}
+
+// prepareBulkArticle 校验权限并生成要写入的文章，规则与单篇新增和更新一致
+func (s *ArticleService) prepareBulkArticle(ctx context.Context, item *dtos.ArticleBulkItem, author *models.User, reserved map[string]bool) (bulkArticle, error) {
+	var current *models.Article
+	if item.ArticleID == 0 {
+		if err := authorize(ctx, s.policy, auth.PermCreate); err != nil {
+			return bulkArticle{}, err
+		}
+	} else {
+		var err error
+		if current, err = s.mysqlRepo.FindArticle(item.ArticleID); err != nil {
+			return bulkArticle{}, err
+		}
+		if err := s.checkEditable(ctx, current); err != nil {
+			return bulkArticle{}, err
+		}
+	}
+
+	// 渲染文章内容
+	content, contentHTML, summary, err := renderContent(item.ContentFormat, item.Content)
+	if err != nil {
+		return bulkArticle{}, err
+	}
+
+	// 查找图片的缩略图及宽度版本
+	pictureVariants, err := s.uploadService.PictureVariants(ctx, item.Picture)
+	if err != nil {
+		return bulkArticle{}, err
+	}
+
+	// 获取分类路径，同时校验分类是否存在
+	categoryPath, err := s.categoryService.CategoryPath(ctx, item.CategoryID)
+	if err != nil {
+		return bulkArticle{}, err
+	}
+
+	// 计算文章状态：新增时没有发布权限默认保存为草稿，更新时未指定则保持不变
+	requestedStatus, currentStatus := item.Status, ""
+	if current != nil {
+		currentStatus = current.Status
+	} else if requestedStatus == "" && !s.policy.Allowed(auth.FromContext(ctx), auth.PermPublish) {
+		requestedStatus = models.ArticleStatusDraft
+	}
+	status, publishedAt, err := resolveStatus(requestedStatus, item.PublishAt, current)
+	if err != nil {
+		return bulkArticle{}, err
+	}
+	if isPublishing(status, currentStatus) {
+		if err := authorize(ctx, s.policy, auth.PermPublish); err != nil {
+			return bulkArticle{}, err
+		}
+	}
+
+	// 生成访问标识，更新时仅在指定了新标识或标题变化时重新生成
+	var articleSlug string
+	slugResolved := current == nil || item.Slug != "" || item.Title != current.Title || current.Slug == ""
+	if !slugResolved {
+		articleSlug = current.Slug
+	} else if articleSlug, err = s.resolveSlug(item.ArticleID, item.Slug, item.Title, reserved); err != nil {
+		return bulkArticle{}, err
+	}
+	reserved[articleSlug] = true
+
+	article := &models.Article{
+		ID:              item.ArticleID,
+		Title:           item.Title,
+		Slug:            articleSlug,
+		Picture:         item.Picture,
+		PictureVariants: pictureVariants,
+		Summary:         summary,
+		ContentFormat:   markup.NormalizeFormat(item.ContentFormat),
+		Tags:            normalizeTags(item.Tags),
+		CategoryID:      item.CategoryID,
+		CategoryPath:    categoryPath,
+		Status:          status,
+		PublishedAt:     publishedAt,
+	}
+	if current == nil {
+		article.AuthorID = author.ID
+		article.AuthorName = author.Name
+		if item.AuthorID > 0 {
+			article.AuthorID = item.AuthorID
+			article.AuthorName = item.AuthorName
+		}
+
+		// 导入的文章保留原系统中的ID和时间
+		if item.ExternalID != "" {
+			article.ExternalID = &item.ExternalID
+		}
+		if item.CreatedAt != nil {
+			article.CreatedAt = *item.CreatedAt
+		}
+		if item.UpdatedAt != nil {
+			article.UpdatedAt = *item.UpdatedAt
+		}
+		// 草稿和定时发布的文章不保留原发布时间
+		if item.PublishedAt != nil && (status == models.ArticleStatusPublished || status == models.ArticleStatusArchived) {
+			article.PublishedAt = item.PublishedAt
+		}
+	} else {
+		article.UpdatedAt = time.Now()
+	}
+
+	return bulkArticle{
+		article: article,
+		articleContent: &models.ArticleContent{
+			ArticleID:   item.ArticleID,
+			Content:     content,
+			ContentHTML: contentHTML,
+		},
+		text:         contentText(article.ContentFormat, content, contentHTML),
+		slugResolved: slugResolved,
+	}, nil
+}
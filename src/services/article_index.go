@@ -0,0 +1,167 @@
+package services
+
+import (
+	"context"
+	"demo/src/errs"
+	"demo/src/repositories"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// catchUpMargin 增量同步时向前多取的时间，覆盖updated_at的秒级精度和实例间的时钟误差
+const catchUpMargin = 5 * time.Second
+
+// CheckArticleIndex 检查别名指向的文章索引是否为当前代码期望的版本，只读不修改索引，启动和导入前调用
+func (s *ArticleService) CheckArticleIndex(ctx context.Context) error {
+	current, err := s.elasticsearchRepo.CurrentArticleIndex(ctx)
+	if err != nil {
+		return err
+	}
+	if current == "" {
+		return errs.ErrArticleIndexMissing
+	}
+	if expected := repositories.ArticleIndexName(); !isArticleIndexVersion(current, expected) {
+		return fmt.Errorf("%w: using %s, expected %s", errs.ErrArticleIndexOutdated, current, expected)
+	}
+	return nil
+}
+
+// isArticleIndexVersion 判断索引是否为指定版本，强制重建的索引名在版本后带有时间后缀
+func isArticleIndexVersion(index, version string) bool {
+	return index == version || strings.HasPrefix(index, version+"_")
+}
+
+// MigrateArticleIndex 按当前的映射和分析器配置新建版本化的文章索引，从MySQL重建全部文档后原子地切换别名。
+// 迁移期间服务继续读写旧索引，切换前后各做一次增量同步，补上重建期间修改的文章，切换后清理已删除的文章；
+// 索引已是当前版本时不做操作，force为true时强制重建。需要先写入同义词文件，返回新索引名和切换前的索引名
+func (s *ArticleService) MigrateArticleIndex(ctx context.Context, force bool) (string, string, error) {
+	current, err := s.elasticsearchRepo.CurrentArticleIndex(ctx)
+	if err != nil {
+		return "", "", err
+	}
+	target := repositories.ArticleIndexName()
+	if isArticleIndexVersion(current, target) && !force {
+		return current, current, nil
+	}
+	if force {
+		// 不能删除别名正在使用的索引，强制重建时使用带时间后缀的新索引
+		target = fmt.Sprintf("%s_%d", target, time.Now().Unix())
+	}
+
+	catchUp, err := s.buildArticleIndex(ctx, target)
+	if err != nil {
+		// 别名仍指向旧索引，删除未完成的新索引
+		if deleteErr := s.elasticsearchRepo.DeleteArticleIndex(context.WithoutCancel(ctx), target); deleteErr != nil {
+			log.Printf("Failed to delete incomplete article index %s: %v", target, deleteErr)
+		}
+		return "", "", err
+	}
+	if err := s.elasticsearchRepo.SwitchArticleIndex(ctx, current, target); err != nil {
+		return "", "", err
+	}
+
+	// 最后一次同步之后写入旧索引的修改，以及重建期间删除的文章
+	if _, err := s.indexArticlesInto(ctx, target, catchUp); err != nil {
+		return target, current, err
+	}
+	if err := s.removeDeletedArticles(ctx, target); err != nil {
+		return target, current, err
+	}
+	return target, current, s.elasticsearchRepo.RefreshArticleIndex(ctx, target)
+}
+
+// buildArticleIndex 创建索引并写入MySQL中的全部文章，再补上重建期间修改的文章，
+// 返回最后一次同步的开始时间，切换别名后从该时间再同步一次
+func (s *ArticleService) buildArticleIndex(ctx context.Context, index string) (time.Time, error) {
+	if err := s.elasticsearchRepo.CreateArticleIndex(ctx, index); err != nil {
+		return time.Time{}, err
+	}
+
+	start := time.Now().Add(-catchUpMargin)
+	count, err := s.indexArticlesInto(ctx, index, time.Time{})
+	if err != nil {
+		return time.Time{}, err
+	}
+	log.Printf("Indexed %d articles into %s", count, index)
+
+	catchUp := time.Now().Add(-catchUpMargin)
+	if _, err := s.indexArticlesInto(ctx, index, start); err != nil {
+		return time.Time{}, err
+	}
+	return catchUp, s.elasticsearchRepo.RefreshArticleIndex(ctx, index)
+}
+
+// indexArticlesInto 将MySQL中的文章按ID顺序逐批写入指定索引，since不为零时只写入此后更新过的文章
+func (s *ArticleService) indexArticlesInto(ctx context.Context, index string, since time.Time) (int, error) {
+	categoryPaths := make(map[uint64][]uint64)
+	count := 0
+	var afterID uint64
+	for {
+		ids, err := s.mysqlRepo.ListArticleIDs(afterID, since, s.bulkBatchSize)
+		if err != nil || len(ids) == 0 {
+			return count, err
+		}
+		afterID = ids[len(ids)-1]
+
+		articles, contents, err := s.mysqlRepo.GetArticles(ids)
+		if err != nil {
+			return count, err
+		}
+		docs := make([]repositories.ArticleBulkDocument, len(articles))
+		for i := range articles {
+			article := &articles[i]
+			path, ok := categoryPaths[article.CategoryID]
+			if !ok {
+				if path, err = s.categoryService.CategoryPath(ctx, article.CategoryID); err != nil {
+					return count, err
+				}
+				categoryPaths[article.CategoryID] = path
+			}
+			article.CategoryPath = path
+
+			content := contents[article.ID]
+			docs[i] = repositories.ArticleBulkDocument{
+				Article: article,
+				Content: contentText(article.ContentFormat, content.Content, content.ContentHTML),
+			}
+		}
+
+		itemErrs, err := s.elasticsearchRepo.IndexArticlesInto(ctx, index, docs)
+		if err != nil {
+			return count, err
+		}
+		for _, itemErr := range itemErrs {
+			if itemErr != nil {
+				return count, itemErr
+			}
+		}
+		count += len(docs)
+
+		if len(ids) < s.bulkBatchSize {
+			return count, nil
+		}
+	}
+}
+
+// removeDeletedArticles 删除索引中MySQL已不存在的文章
+func (s *ArticleService) removeDeletedArticles(ctx context.Context, index string) error {
+	return s.elasticsearchRepo.ScanArticleIndexIDs(ctx, index, s.bulkBatchSize, func(ids []uint64) error {
+		existing, err := s.mysqlRepo.ExistingArticleIDs(ids)
+		if err != nil {
+			return err
+		}
+
+		var deleted []uint64
+		for _, id := range ids {
+			if !existing[id] {
+				deleted = append(deleted, id)
+			}
+		}
+		if len(deleted) == 0 {
+			return nil
+		}
+		return s.elasticsearchRepo.DeleteArticlesFrom(ctx, index, deleted)
+	})
+}
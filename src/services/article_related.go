@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"demo/src/dtos"
+	"log"
+)
+
+const (
+	defaultRelatedSize = 5  // 默认返回的相关文章数量
+	maxRelatedSize     = 20 // 缓存的相关文章数量，按请求数量截取
+)
+
+// RelatedArticles 获取相关文章，结果按文章缓存，文章更新时清除；源文章不可见时返回ErrArticleNotFound，
+// 缓存中的文章可能已被删除、归档或取消发布，返回前按当前状态过滤
+func (s *ArticleService) RelatedArticles(ctx context.Context, articleID uint64, size int) ([]dtos.RelatedArticle, error) {
+	if size == 0 {
+		size = defaultRelatedSize
+	}
+
+	// 确认文章存在且可见，与文章详情接口一致
+	if _, err := s.findReadableArticle(ctx, articleID); err != nil {
+		return nil, err
+	}
+
+	// 优先读取缓存，缓存不可用时直接查询ES
+	articles, err := s.redisRepo.GetRelatedArticles(ctx, articleID)
+	if err != nil {
+		log.Printf("Failed to read related articles cache! ID: %d, Error: %v", articleID, err)
+	}
+
+	if articles == nil {
+		// 取标签用于提升有相同标签的文章
+		tags, err := s.mysqlRepo.GetArticleTags(articleID)
+		if err != nil {
+			return nil, err
+		}
+
+		articles, err = s.elasticsearchRepo.RelatedArticles(ctx, articleID, tags, maxRelatedSize)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := s.redisRepo.SetRelatedArticles(ctx, articleID, articles, s.relatedCacheTTL); err != nil {
+			log.Printf("Failed to write related articles cache! ID: %d, Error: %v", articleID, err)
+		}
+	}
+
+	articles, err = s.publishedRelatedArticles(articles)
+	if err != nil {
+		return nil, err
+	}
+	if len(articles) > size {
+		articles = articles[:size]
+	}
+	return articles, nil
+}
+
+// publishedRelatedArticles 去掉当前已不是发布状态的文章
+func (s *ArticleService) publishedRelatedArticles(articles []dtos.RelatedArticle) ([]dtos.RelatedArticle, error) {
+	if len(articles) == 0 {
+		return articles, nil
+	}
+
+	ids := make([]uint64, len(articles))
+	for i, article := range articles {
+		ids[i] = article.ID
+	}
+	published, err := s.mysqlRepo.PublishedArticleIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]dtos.RelatedArticle, 0, len(articles))
+	for _, article := range articles {
+		if published[article.ID] {
+			filtered = append(filtered, article)
+		}
+	}
+	return filtered, nil
+}
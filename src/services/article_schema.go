@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"log"
+)
+
+// MigrateSchema 升级旧版本部署的数据库：补充article和article_content表缺少的列，将标签名转为小写，为已有文章生成访问标识和HTML内容，
+// 最后添加索引。新增的表需要先执行init.sql创建，每一步已完成时跳过，中断后可以重新执行
+func (s *ArticleService) MigrateSchema(ctx context.Context) error {
+	columns, err := s.mysqlRepo.MigrateLegacyColumns()
+	for _, column := range columns {
+		log.Printf("Added column %s", column)
+	}
+	if err != nil {
+		return err
+	}
+
+	renamed, err := s.mysqlRepo.LowercaseTagNames()
+	if err != nil {
+		return err
+	}
+	if renamed > 0 {
+		log.Printf("Lowercased %d tag names", renamed)
+	}
+
+	if err := s.backfillSlugs(ctx); err != nil {
+		return err
+	}
+	if err := s.backfillContentHTML(ctx); err != nil {
+		return err
+	}
+
+	indexes, err := s.mysqlRepo.MigrateLegacyIndexes()
+	for _, index := range indexes {
+		log.Printf("Added index %s", index)
+	}
+	return err
+}
+
+// backfillSlugs 为没有访问标识的文章按标题生成访问标识
+func (s *ArticleService) backfillSlugs(ctx context.Context) error {
+	count := 0
+	var afterID uint64
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		articles, err := s.mysqlRepo.ListArticlesWithoutSlug(afterID, s.bulkBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, article := range articles {
+			articleSlug, err := s.resolveSlug(article.ID, "", article.Title, nil)
+			if err != nil {
+				return err
+			}
+			if err := s.mysqlRepo.UpdateArticleSlug(article.ID, articleSlug); err != nil {
+				return err
+			}
+			afterID = article.ID
+		}
+		count += len(articles)
+		if len(articles) < s.bulkBatchSize {
+			if count > 0 {
+				log.Printf("Generated slugs for %d articles", count)
+			}
+			return nil
+		}
+	}
+}
+
+// backfillContentHTML 为没有HTML内容的文章按内容格式渲染HTML内容
+func (s *ArticleService) backfillContentHTML(ctx context.Context) error {
+	count := 0
+	var afterID uint64
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		contents, formats, err := s.mysqlRepo.ListContentsWithoutHTML(afterID, s.bulkBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, content := range contents {
+			_, contentHTML, _, err := renderContent(formats[content.ArticleID], content.Content)
+			if err != nil {
+				return err
+			}
+			if err := s.mysqlRepo.UpdateContentHTML(content.ArticleID, contentHTML); err != nil {
+				return err
+			}
+			afterID = content.ArticleID
+		}
+		count += len(contents)
+		if len(contents) < s.bulkBatchSize {
+			if count > 0 {
+				log.Printf("Rendered HTML content for %d articles", count)
+			}
+			return nil
+		}
+	}
+}
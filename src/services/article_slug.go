@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"demo/src/dtos"
+	"demo/src/errs"
+	"fmt"
+	"github.com/gosimple/slug"
+	"strings"
+)
+
+const (
+	maxSlugLength   = 150       // 访问标识最大长度，预留重名时追加序号的空间
+	fallbackSlug    = "article" // 标题无法转写为访问标识时（如全部为表情符号）使用的默认值
+	maxSlugAttempts = 100       // 重名时最多尝试的序号
+)
+
+// resolveSlug 生成文章访问标识：优先使用客户端指定的值，否则由标题生成（中文转写为拼音）；
+// 由标题生成的标识重名时追加序号，客户端指定的标识重名时返回错误；reserved为批量写入中已分配但尚未保存的标识
+func (s *ArticleService) resolveSlug(articleID uint64, requested, title string, reserved map[string]bool) (string, error) {
+	explicit := requested != ""
+	source := title
+	if explicit {
+		source = requested
+	}
+
+	base := normalizeSlug(source)
+	if base == "" {
+		base = fallbackSlug
+	}
+
+	for i := 1; i <= maxSlugAttempts; i++ {
+		candidate := base
+		if i > 1 {
+			candidate = fmt.Sprintf("%s-%d", base, i)
+		}
+		if reserved[candidate] {
+			if explicit {
+				return "", errs.ErrSlugTaken
+			}
+			continue
+		}
+
+		owner, err := s.mysqlRepo.SlugOwner(candidate)
+		if err != nil {
+			return "", err
+		}
+		if owner == 0 || owner == articleID {
+			return candidate, nil
+		}
+		if explicit {
+			return "", errs.ErrSlugTaken
+		}
+	}
+
+	return "", errs.ErrSlugTaken
+}
+
+// normalizeSlug 将标题或指定的标识转写为访问标识格式（小写、连字符分隔、中文转写为拼音）并截断，无法转写时返回空字符串
+func normalizeSlug(source string) string {
+	base := slug.Make(source)
+	if len(base) > maxSlugLength {
+		base = strings.Trim(base[:maxSlugLength], "-")
+	}
+	return base
+}
+
+// GetArticleBySlug 根据访问标识获取文章详情，访问标识是历史值时返回当前访问标识用于跳转
+func (s *ArticleService) GetArticleBySlug(ctx context.Context, articleSlug string) (article *dtos.ArticleDetailData, redirectSlug string, err error) {
+	found, redirected, err := s.mysqlRepo.FindArticleBySlug(articleSlug)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := s.checkReadable(ctx, found); err != nil {
+		return nil, "", err
+	}
+	if redirected {
+		return nil, found.Slug, nil
+	}
+
+	article, err = s.GetArticle(ctx, found.ID)
+	return article, "", err
+}
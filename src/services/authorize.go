@@ -0,0 +1,26 @@
+package services
+
+import (
+	"context"
+	"demo/src/common/auth"
+	"demo/src/errs"
+	"demo/src/models"
+)
+
+// authorize 检查当前调用方是否拥有指定权限
+func authorize(ctx context.Context, policy *auth.Policy, permission string) error {
+	principal := auth.FromContext(ctx)
+	if principal == nil {
+		return errs.ErrUnauthenticated
+	}
+	if !policy.Allowed(principal, permission) {
+		return errs.NewPermissionError(permission)
+	}
+	return nil
+}
+
+// isPublishing 判断状态变更是否属于发布操作，需要publish权限
+func isPublishing(status, currentStatus string) bool {
+	return status != currentStatus &&
+		(status == models.ArticleStatusPublished || status == models.ArticleStatusScheduled)
+}
@@ -0,0 +1,221 @@
+package services
+
+import (
+	"context"
+	"demo/src/common/auth"
+	"demo/src/dtos"
+	"demo/src/errs"
+	"demo/src/models"
+	"demo/src/repositories"
+	"errors"
+	"github.com/elastic/go-elasticsearch/v8"
+	"gorm.io/gorm"
+	"log"
+)
+
+type CategoryService struct {
+	mysqlRepo         *repositories.MySQLRepository
+	elasticsearchRepo *repositories.ElasticsearchRepository
+	policy            *auth.Policy
+}
+
+func NewCategoryService(db *gorm.DB, esClient *elasticsearch.Client, policy *auth.Policy) *CategoryService {
+	return &CategoryService{
+		mysqlRepo:         repositories.NewMySQLRepository(db),
+		elasticsearchRepo: repositories.NewElasticsearchRepository(esClient),
+		policy:            policy,
+	}
+}
+
+// loadCategories 加载全部分类并按ID索引，分类数量较少，直接在内存中计算层级关系
+func (s *CategoryService) loadCategories() (map[uint64]*models.Category, error) {
+	categories, err := s.mysqlRepo.ListCategories()
+	if err != nil {
+		return nil, err
+	}
+
+	categoryMap := make(map[uint64]*models.Category, len(categories))
+	for i := range categories {
+		categoryMap[categories[i].ID] = &categories[i]
+	}
+	return categoryMap, nil
+}
+
+// buildPath 计算从顶级分类到指定分类的ID路径
+func buildPath(categoryMap map[uint64]*models.Category, categoryID uint64) ([]uint64, error) {
+	var path []uint64
+	for id := categoryID; id != 0; id = categoryMap[id].ParentID {
+		if _, ok := categoryMap[id]; !ok {
+			return nil, errs.ErrCategoryNotFound
+		}
+		// 防御数据异常导致的环
+		if len(path) > len(categoryMap) {
+			return nil, errs.ErrInvalidParentCategory
+		}
+		path = append([]uint64{id}, path...)
+	}
+	return path, nil
+}
+
+// CategoryPath 获取分类路径，categoryID为0（未分类）时返回空路径
+func (s *CategoryService) CategoryPath(ctx context.Context, categoryID uint64) ([]uint64, error) {
+	if categoryID == 0 {
+		return []uint64{}, nil
+	}
+
+	categoryMap, err := s.loadCategories()
+	if err != nil {
+		return nil, err
+	}
+	return buildPath(categoryMap, categoryID)
+}
+
+// ListCategories 获取分类树
+func (s *CategoryService) ListCategories(ctx context.Context) ([]*models.Category, error) {
+	categories, err := s.mysqlRepo.ListCategories()
+	if err != nil {
+		return nil, err
+	}
+
+	categoryMap := make(map[uint64]*models.Category, len(categories))
+	for i := range categories {
+		categoryMap[categories[i].ID] = &categories[i]
+	}
+
+	roots := []*models.Category{}
+	for i := range categories {
+		category := &categories[i]
+		if parent, ok := categoryMap[category.ParentID]; ok {
+			parent.Children = append(parent.Children, category)
+		} else {
+			roots = append(roots, category)
+		}
+	}
+	return roots, nil
+}
+
+// GetCategory 获取分类详情
+func (s *CategoryService) GetCategory(ctx context.Context, categoryID uint64) (*dtos.CategoryDetailData, error) {
+	categoryMap, err := s.loadCategories()
+	if err != nil {
+		return nil, err
+	}
+
+	category, ok := categoryMap[categoryID]
+	if !ok {
+		return nil, errs.ErrCategoryNotFound
+	}
+
+	path, err := buildPath(categoryMap, categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dtos.CategoryDetailData{
+		Category: *category,
+		Path:     path,
+	}, nil
+}
+
+// AddCategory 新增分类
+func (s *CategoryService) AddCategory(ctx context.Context, req *dtos.CategoryAddRequest) (uint64, error) {
+	if err := authorize(ctx, s.policy, auth.PermManageTaxonomy); err != nil {
+		return 0, err
+	}
+
+	if req.ParentID != 0 {
+		if _, err := s.mysqlRepo.GetCategory(req.ParentID); err != nil {
+			if errors.Is(err, errs.ErrCategoryNotFound) {
+				return 0, errs.ErrInvalidParentCategory
+			}
+			return 0, err
+		}
+	}
+
+	category := models.Category{
+		ParentID: req.ParentID,
+		Name:     req.Name,
+	}
+	if err := s.mysqlRepo.AddCategory(&category); err != nil {
+		return 0, err
+	}
+	return category.ID, nil
+}
+
+// UpdateCategory 更新分类，父分类变化时同步更新子树下所有文章在ES中的分类路径
+func (s *CategoryService) UpdateCategory(ctx context.Context, categoryID uint64, req *dtos.CategoryUpdateRequest) error {
+	if err := authorize(ctx, s.policy, auth.PermManageTaxonomy); err != nil {
+		return err
+	}
+
+	categoryMap, err := s.loadCategories()
+	if err != nil {
+		return err
+	}
+
+	category, ok := categoryMap[categoryID]
+	if !ok {
+		return errs.ErrCategoryNotFound
+	}
+
+	// 新的父分类必须存在，且不能是自身或自身的子分类
+	if req.ParentID != 0 {
+		parentPath, err := buildPath(categoryMap, req.ParentID)
+		if err != nil {
+			return errs.ErrInvalidParentCategory
+		}
+		for _, id := range parentPath {
+			if id == categoryID {
+				return errs.ErrInvalidParentCategory
+			}
+		}
+	}
+
+	moved := category.ParentID != req.ParentID
+	category.Name = req.Name
+	category.ParentID = req.ParentID
+	if err := s.mysqlRepo.UpdateCategory(category); err != nil {
+		return err
+	}
+
+	if !moved {
+		return nil
+	}
+
+	// 重新计算子树中每个分类的路径并同步到ES
+	for _, id := range subtreeIDs(categoryMap, categoryID) {
+		path, err := buildPath(categoryMap, id)
+		if err != nil {
+			return err
+		}
+		if err := s.elasticsearchRepo.UpdateCategoryPath(ctx, id, path); err != nil {
+			log.Printf("Failed to update category path in ES! category ID: %d, Error: %v", id, err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// subtreeIDs 获取分类及其所有子孙分类的ID
+func subtreeIDs(categoryMap map[uint64]*models.Category, categoryID uint64) []uint64 {
+	children := make(map[uint64][]uint64)
+	for id, category := range categoryMap {
+		children[category.ParentID] = append(children[category.ParentID], id)
+	}
+
+	ids := []uint64{categoryID}
+	for i := 0; i < len(ids); i++ {
+		ids = append(ids, children[ids[i]]...)
+	}
+	return ids
+}
+
+// DeleteCategory 删除分类
+func (s *CategoryService) DeleteCategory(ctx context.Context, categoryID uint64) error {
+	if err := authorize(ctx, s.policy, auth.PermManageTaxonomy); err != nil {
+		return err
+	}
+
+	return s.mysqlRepo.DeleteCategory(categoryID)
+}
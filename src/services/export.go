@@ -0,0 +1,229 @@
+package services
+
+import (
+	"context"
+	"demo/src/common/auth"
+	"demo/src/common/env"
+	"demo/src/dtos"
+	"demo/src/repositories"
+	"encoding/csv"
+	"encoding/json"
+	"github.com/elastic/go-elasticsearch/v8"
+	"gorm.io/gorm"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 导出格式
+const (
+	ExportFormatNDJSON = "ndjson" // 每行一篇文章的JSON
+	ExportFormatCSV    = "csv"
+	ExportFormatJSON   = "json" // 文章JSON数组
+)
+
+// exportCSVHeader CSV导出的列
+var exportCSVHeader = []string{
+	"id", "title", "slug", "picture", "summary", "content_format", "content", "content_html", "tags",
+	"category_id", "author_id", "author_name", "status", "published_at", "created_at", "updated_at",
+}
+
+type ExportService struct {
+	mysqlRepo         *repositories.MySQLRepository
+	elasticsearchRepo *repositories.ElasticsearchRepository
+	policy            *auth.Policy
+	batchSize         int
+}
+
+func NewExportService(db *gorm.DB, esClient *elasticsearch.Client, policy *auth.Policy) *ExportService {
+	return &ExportService{
+		mysqlRepo:         repositories.NewMySQLRepository(db),
+		elasticsearchRepo: repositories.NewElasticsearchRepository(esClient),
+		policy:            policy,
+		batchSize:         env.Int("EXPORT_BATCH_SIZE", 500),
+	}
+}
+
+// ExportArticles 按列表的过滤条件导出文章（包含内容）到w：从ES的同一时间点快照中逐批获取文章ID，再从数据库读取文章，
+// 每批写入后刷新w，不会一次加载全部文章。默认只导出已发布的文章，拥有export权限即可通过status导出其他状态的文章
+func (s *ExportService) ExportArticles(ctx context.Context, w io.Writer, req *dtos.ArticleExportRequest) error {
+	if err := authorize(ctx, s.policy, auth.PermExport); err != nil {
+		return err
+	}
+	req.Tags = normalizeTags(req.Tags)
+	var err error
+	if req.Status, err = normalizeStatusFilter(req.Status); err != nil {
+		return err
+	}
+
+	writer := newArticleExportWriter(req.Format, w)
+	err = s.elasticsearchRepo.ScanArticleIDs(ctx, &req.ArticleFilter, req.Sort, req.Order, s.batchSize, func(ids []uint64) error {
+		articles, contents, err := s.mysqlRepo.GetArticles(ids)
+		if err != nil {
+			return err
+		}
+		for _, article := range articles {
+			content := contents[article.ID]
+			if err := writer.Write(&dtos.ArticleDetailData{
+				Article:     article,
+				Content:     content.Content,
+				ContentHTML: content.ContentHTML,
+			}); err != nil {
+				return err
+			}
+		}
+		return writer.Flush()
+	})
+	if err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
+// articleExportWriter 按导出格式写入文章
+type articleExportWriter interface {
+	Write(article *dtos.ArticleDetailData) error
+	Flush() error // 将已写入的内容发送出去
+	Close() error // 写入格式的结尾
+}
+
+// newArticleExportWriter 创建导出格式对应的writer，默认NDJSON
+func newArticleExportWriter(format string, w io.Writer) articleExportWriter {
+	switch format {
+	case ExportFormatCSV:
+		return &csvExportWriter{w: w, csv: csv.NewWriter(w)}
+	case ExportFormatJSON:
+		return &jsonExportWriter{w: w}
+	default:
+		return &ndjsonExportWriter{w: w, encoder: json.NewEncoder(w)}
+	}
+}
+
+// flushWriter w支持时（如HTTP响应）立即发送已写入的内容
+func flushWriter(w io.Writer) {
+	if flusher, ok := w.(interface{ Flush() }); ok {
+		flusher.Flush()
+	}
+}
+
+type ndjsonExportWriter struct {
+	w       io.Writer
+	encoder *json.Encoder
+}
+
+func (e *ndjsonExportWriter) Write(article *dtos.ArticleDetailData) error {
+	return e.encoder.Encode(article)
+}
+
+func (e *ndjsonExportWriter) Flush() error {
+	flushWriter(e.w)
+	return nil
+}
+
+func (e *ndjsonExportWriter) Close() error {
+	return e.Flush()
+}
+
+type jsonExportWriter struct {
+	w     io.Writer
+	count int
+}
+
+func (e *jsonExportWriter) Write(article *dtos.ArticleDetailData) error {
+	separator := ","
+	if e.count == 0 {
+		separator = "["
+	}
+	data, err := json.Marshal(article)
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(e.w, separator); err != nil {
+		return err
+	}
+	if _, err := e.w.Write(data); err != nil {
+		return err
+	}
+	e.count++
+	return nil
+}
+
+func (e *jsonExportWriter) Flush() error {
+	flushWriter(e.w)
+	return nil
+}
+
+func (e *jsonExportWriter) Close() error {
+	end := "]\n"
+	if e.count == 0 {
+		end = "[]\n"
+	}
+	if _, err := io.WriteString(e.w, end); err != nil {
+		return err
+	}
+	return e.Flush()
+}
+
+type csvExportWriter struct {
+	w             io.Writer
+	csv           *csv.Writer
+	headerWritten bool
+}
+
+// writeHeader 写入表头，没有文章时也会写入
+func (e *csvExportWriter) writeHeader() error {
+	if e.headerWritten {
+		return nil
+	}
+	e.headerWritten = true
+	return e.csv.Write(exportCSVHeader)
+}
+
+func (e *csvExportWriter) Write(article *dtos.ArticleDetailData) error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	return e.csv.Write([]string{
+		strconv.FormatUint(article.ID, 10),
+		article.Title,
+		article.Slug,
+		article.Picture,
+		article.Summary,
+		article.ContentFormat,
+		article.Content,
+		article.ContentHTML,
+		strings.Join(article.Tags, ","),
+		strconv.FormatUint(article.CategoryID, 10),
+		strconv.FormatUint(article.AuthorID, 10),
+		article.AuthorName,
+		article.Status,
+		formatExportTime(article.PublishedAt),
+		article.CreatedAt.Format(time.RFC3339),
+		article.UpdatedAt.Format(time.RFC3339),
+	})
+}
+
+func (e *csvExportWriter) Flush() error {
+	e.csv.Flush()
+	if err := e.csv.Error(); err != nil {
+		return err
+	}
+	flushWriter(e.w)
+	return nil
+}
+
+func (e *csvExportWriter) Close() error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	return e.Flush()
+}
+
+// formatExportTime 格式化可为空的时间，为空时返回空字符串
+func formatExportTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,487 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"demo/src/common/auth"
+	"demo/src/common/env"
+	"demo/src/common/markup"
+	"demo/src/dtos"
+	"demo/src/models"
+	"demo/src/repositories"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin/binding"
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 导入格式
+const (
+	ImportFormatNDJSON   = "ndjson"   // 导出的NDJSON文件
+	ImportFormatCSV      = "csv"      // 导出的CSV文件
+	ImportFormatMarkdown = "markdown" // 包含YAML front matter的Markdown文件目录
+)
+
+// importRecord 导入文件中的一篇文章，字段与导出格式一致，Markdown文件的front matter使用相同的字段名
+type importRecord struct {
+	ID            uint64     `json:"id" yaml:"id"`
+	ExternalID    string     `json:"external_id" yaml:"external_id"`
+	Title         string     `json:"title" yaml:"title"`
+	Slug          string     `json:"slug" yaml:"slug"`
+	Picture       string     `json:"picture" yaml:"picture"`
+	Content       string     `json:"content" yaml:"-"`
+	ContentFormat string     `json:"content_format" yaml:"content_format"`
+	Tags          []string   `json:"tags" yaml:"tags"`
+	CategoryID    uint64     `json:"category_id" yaml:"category_id"`
+	Author        string     `json:"author" yaml:"author"`           // 作者在身份认证服务中的subject
+	AuthorName    string     `json:"author_name" yaml:"author_name"` // 作者不存在时创建用户使用的名称
+	Status        string     `json:"status" yaml:"status"`
+	PublishedAt   *time.Time `json:"published_at" yaml:"published_at"`
+	CreatedAt     *time.Time `json:"created_at" yaml:"created_at"`
+	UpdatedAt     *time.Time `json:"updated_at" yaml:"updated_at"`
+}
+
+// ImportFailure 导入失败的文章
+type ImportFailure struct {
+	Source string // 文章在导入文件中的位置，如行号或文件名
+	Error  string
+}
+
+// ImportSummary 导入结果统计
+type ImportSummary struct {
+	Created  int
+	Skipped  int // 原系统ID或访问标识已存在的文章
+	Failed   int
+	Failures []ImportFailure
+}
+
+// fail 记录导入失败的文章
+func (s *ImportSummary) fail(source string, err error) {
+	s.Failed++
+	s.Failures = append(s.Failures, ImportFailure{Source: source, Error: err.Error()})
+}
+
+// pendingImport 等待写入的文章
+type pendingImport struct {
+	source string
+	item   dtos.ArticleBulkItem
+}
+
+type ImportService struct {
+	mysqlRepo      *repositories.MySQLRepository
+	articleService *ArticleService
+	batchSize      int
+}
+
+func NewImportService(db *gorm.DB, articleService *ArticleService) *ImportService {
+	return &ImportService{
+		mysqlRepo:      repositories.NewMySQLRepository(db),
+		articleService: articleService,
+		batchSize:      env.Int("IMPORT_BATCH_SIZE", 500),
+	}
+}
+
+// ImportArticles 从导出的NDJSON/CSV文件或Markdown目录导入文章，保留原始时间；
+// 原系统ID（没有时使用原文章ID或Markdown文件的相对路径）或指定的访问标识已存在时跳过。
+// 文章按批次通过批量写入保存，单篇失败记录在结果中，读取文件或批量写入本身失败时返回err
+func (s *ImportService) ImportArticles(ctx context.Context, format, path string) (*ImportSummary, error) {
+	summary := &ImportSummary{}
+	seen := make(map[string]bool)            // 本次导入中已出现的原系统ID
+	authors := make(map[string]*models.User) // 本次导入中已查找的作者，按subject索引
+
+	// 各批次写入时不刷新索引，导入结束后统一刷新一次
+	batchCtx := repositories.WithRefresh(ctx, repositories.RefreshFalse)
+	defer s.articleService.RefreshArticles(ctx)
+	var batch []pendingImport
+
+	add := func(source string, record *importRecord, parseErr error) error {
+		if parseErr != nil {
+			summary.fail(source, parseErr)
+			return nil
+		}
+		item, err := record.bulkItem()
+		if err != nil {
+			summary.fail(source, err)
+			return nil
+		}
+		if record.Author != "" {
+			author, err := s.importAuthor(authors, record.Author, record.AuthorName)
+			if errors.Is(err, auth.ErrReservedSubject) {
+				summary.fail(source, err)
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			item.AuthorID, item.AuthorName = author.ID, author.Name
+		}
+		batch = append(batch, pendingImport{source: source, item: item})
+		if len(batch) < s.batchSize {
+			return nil
+		}
+		err = s.importBatch(batchCtx, batch, seen, summary)
+		batch = nil
+		return err
+	}
+
+	var err error
+	switch format {
+	case ImportFormatNDJSON:
+		err = readNDJSON(path, add)
+	case ImportFormatCSV:
+		err = readCSV(path, add)
+	case ImportFormatMarkdown:
+		err = readMarkdownDir(path, add)
+	default:
+		err = fmt.Errorf("unknown import format %q", format)
+	}
+	if err != nil {
+		return summary, err
+	}
+
+	if len(batch) > 0 {
+		err = s.importBatch(batchCtx, batch, seen, summary)
+	}
+	return summary, err
+}
+
+// importAuthor 按subject查找导入文章的作者，用户不存在时按名称（没有时使用subject）创建，
+// 该用户之后登录时按subject对应到同一用户；不能使用API Key或命令行调用方保留的subject
+func (s *ImportService) importAuthor(authors map[string]*models.User, subject, name string) (*models.User, error) {
+	if auth.IsReservedSubject(subject) {
+		return nil, fmt.Errorf("%w: %q", auth.ErrReservedSubject, subject)
+	}
+	if author, ok := authors[subject]; ok {
+		return author, nil
+	}
+
+	author, err := s.mysqlRepo.FindUserBySubject(subject)
+	if err != nil {
+		return nil, err
+	}
+	if author == nil {
+		if name == "" {
+			name = subject
+		}
+		if author, _, err = s.mysqlRepo.UpsertUser(subject, name); err != nil {
+			return nil, err
+		}
+	}
+	authors[subject] = author
+	return author, nil
+}
+
+// importBatch 去重后批量写入一批文章，访问标识已在bulkItem中转写，与写入时生成的标识一致
+func (s *ImportService) importBatch(ctx context.Context, batch []pendingImport, seen map[string]bool, summary *ImportSummary) error {
+	externalIDs := make([]string, 0, len(batch))
+	for _, pending := range batch {
+		externalIDs = append(externalIDs, pending.item.ExternalID)
+	}
+	existing, err := s.mysqlRepo.ExistingExternalIDs(externalIDs)
+	if err != nil {
+		return err
+	}
+
+	var sources []string
+	var items []dtos.ArticleBulkItem
+	for _, pending := range batch {
+		externalID := pending.item.ExternalID
+		if existing[externalID] || seen[externalID] {
+			summary.Skipped++
+			continue
+		}
+		seen[externalID] = true
+
+		if pending.item.Slug != "" {
+			owner, err := s.mysqlRepo.SlugOwner(pending.item.Slug)
+			if err != nil {
+				return err
+			}
+			if owner > 0 {
+				summary.Skipped++
+				continue
+			}
+		}
+
+		sources = append(sources, pending.source)
+		items = append(items, pending.item)
+	}
+	if len(items) == 0 {
+		return nil
+	}
+
+	outcomes, err := s.articleService.BulkSaveArticles(ctx, items)
+	if err != nil {
+		return err
+	}
+	for i, outcome := range outcomes {
+		if outcome.Err != nil {
+			summary.fail(sources[i], outcome.Err)
+		} else {
+			summary.Created++
+		}
+	}
+	return nil
+}
+
+// bulkItem 将导入的文章转换为批量写入的新增文章并校验
+func (r *importRecord) bulkItem() (dtos.ArticleBulkItem, error) {
+	externalID := r.ExternalID
+	if externalID == "" && r.ID > 0 {
+		externalID = strconv.FormatUint(r.ID, 10)
+	}
+	if externalID == "" {
+		return dtos.ArticleBulkItem{}, errors.New("missing external_id")
+	}
+
+	item := dtos.ArticleBulkItem{
+		ArticleUpdateRequest: dtos.ArticleUpdateRequest{
+			Title:         r.Title,
+			Slug:          normalizeSlug(r.Slug),
+			Picture:       r.Picture,
+			Content:       r.Content,
+			ContentFormat: r.ContentFormat,
+			Tags:          normalizeTags(r.Tags),
+			CategoryID:    r.CategoryID,
+			Status:        r.Status,
+		},
+		ExternalID:  externalID,
+		CreatedAt:   r.CreatedAt,
+		UpdatedAt:   r.UpdatedAt,
+		PublishedAt: r.PublishedAt,
+	}
+	if r.Status == models.ArticleStatusScheduled {
+		item.PublishAt = r.PublishedAt
+	}
+	// 已发布和归档的文章没有发布时间时使用创建时间，没有更新时间时使用创建时间
+	published := r.Status == "" || r.Status == models.ArticleStatusPublished || r.Status == models.ArticleStatusArchived
+	if item.PublishedAt == nil && published {
+		item.PublishedAt = r.CreatedAt
+	}
+	if item.UpdatedAt == nil {
+		item.UpdatedAt = r.CreatedAt
+	}
+
+	if err := binding.Validator.ValidateStruct(&item); err != nil {
+		return dtos.ArticleBulkItem{}, err
+	}
+	return item, nil
+}
+
+// importAdder 接收读取到的一篇文章，解析失败时parseErr不为空
+type importAdder func(source string, record *importRecord, parseErr error) error
+
+// readNDJSON 读取NDJSON文件，每行一篇文章
+func readNDJSON(path string, add importAdder) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	reader := bufio.NewReader(f)
+	for line := 1; ; line++ {
+		data, readErr := reader.ReadBytes('\n')
+		if readErr != nil && readErr != io.EOF {
+			return readErr
+		}
+		if len(bytes.TrimSpace(data)) > 0 {
+			var record importRecord
+			parseErr := json.Unmarshal(data, &record)
+			if err := add(fmt.Sprintf("line %d", line), &record, parseErr); err != nil {
+				return err
+			}
+		}
+		if readErr == io.EOF {
+			return nil
+		}
+	}
+}
+
+// readCSV 读取CSV文件，第一行为列名，列与导出格式一致，标签以逗号分隔
+func readCSV(path string, add importAdder) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return fmt.Errorf("error reading CSV header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+
+	for row := 2; ; row++ {
+		values, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		source := fmt.Sprintf("row %d", row)
+		if err != nil {
+			if _, ok := err.(*csv.ParseError); !ok {
+				return err
+			}
+			if err := add(source, nil, err); err != nil {
+				return err
+			}
+			continue
+		}
+
+		record, parseErr := parseCSVRecord(columns, values)
+		if err := add(source, record, parseErr); err != nil {
+			return err
+		}
+	}
+}
+
+// parseCSVRecord 按列名解析CSV的一行
+func parseCSVRecord(columns map[string]int, values []string) (*importRecord, error) {
+	get := func(name string) string {
+		if i, ok := columns[name]; ok && i < len(values) {
+			return strings.TrimSpace(values[i])
+		}
+		return ""
+	}
+	parseUint := func(name string) (uint64, error) {
+		value := get(name)
+		if value == "" {
+			return 0, nil
+		}
+		n, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s: %w", name, err)
+		}
+		return n, nil
+	}
+	parseTime := func(name string) (*time.Time, error) {
+		value := get(name)
+		if value == "" {
+			return nil, nil
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", name, err)
+		}
+		return &t, nil
+	}
+
+	record := &importRecord{
+		ExternalID:    get("external_id"),
+		Title:         get("title"),
+		Slug:          get("slug"),
+		Picture:       get("picture"),
+		Content:       get("content"),
+		ContentFormat: get("content_format"),
+		Tags:          []string{get("tags")},
+		Author:        get("author"),
+		AuthorName:    get("author_name"),
+		Status:        get("status"),
+	}
+	var err error
+	if record.ID, err = parseUint("id"); err != nil {
+		return nil, err
+	}
+	if record.CategoryID, err = parseUint("category_id"); err != nil {
+		return nil, err
+	}
+	if record.PublishedAt, err = parseTime("published_at"); err != nil {
+		return nil, err
+	}
+	if record.CreatedAt, err = parseTime("created_at"); err != nil {
+		return nil, err
+	}
+	if record.UpdatedAt, err = parseTime("updated_at"); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// readMarkdownDir 读取目录（包括子目录）中的Markdown文件，文件开头---之间为YAML front matter，
+// 原系统ID默认使用文件的相对路径，访问标识默认使用文件名
+func readMarkdownDir(dir string, add importAdder) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.EqualFold(filepath.Ext(path), ".md") {
+			return nil
+		}
+
+		source, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		source = filepath.ToSlash(source)
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		record, parseErr := parseMarkdown(data)
+		if parseErr == nil {
+			if record.ExternalID == "" {
+				record.ExternalID = source
+			}
+			if record.Slug == "" {
+				record.Slug = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+			}
+		}
+		return add(source, record, parseErr)
+	})
+}
+
+// cutFrontMatter 在开始分隔符之后查找单独成行的结束分隔符---，返回分隔符之前的front matter和之后的正文
+func cutFrontMatter(s string) (frontMatter, body string, found bool) {
+	for offset := 0; offset < len(s); {
+		line, next := s[offset:], len(s)
+		if end := strings.IndexByte(line, '\n'); end >= 0 {
+			line, next = line[:end], offset+end+1
+		}
+		if strings.TrimRight(line, " \t") == "---" {
+			return s[:offset], s[next:], true
+		}
+		offset = next
+	}
+	return "", "", false
+}
+
+// parseMarkdown 解析Markdown文件的front matter和正文
+func parseMarkdown(data []byte) (*importRecord, error) {
+	record := &importRecord{ContentFormat: markup.FormatMarkdown}
+	content := string(bytes.TrimPrefix(data, []byte("\ufeff")))
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+
+	if rest, ok := strings.CutPrefix(content, "---\n"); ok {
+		frontMatter, body, found := cutFrontMatter(rest)
+		if !found {
+			return nil, errors.New("unterminated front matter")
+		}
+		if err := yaml.Unmarshal([]byte(frontMatter), record); err != nil {
+			return nil, fmt.Errorf("invalid front matter: %w", err)
+		}
+		content = body
+		if record.ContentFormat == "" {
+			record.ContentFormat = markup.FormatMarkdown
+		}
+	}
+
+	record.Content = strings.TrimSpace(content)
+	return record, nil
+}
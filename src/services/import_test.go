@@ -0,0 +1,169 @@
+package services
+
+import (
+	"demo/src/common/auth"
+	"demo/src/models"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestImportRecordBulkItem(t *testing.T) {
+	created := time.Date(2023, 5, 1, 8, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name          string
+		record        importRecord
+		wantSlug      string
+		wantPublished bool
+	}{
+		{name: "published without published_at", record: importRecord{ID: 1, Title: "a", Status: "published", CreatedAt: &created}, wantPublished: true},
+		{name: "default status", record: importRecord{ID: 1, Title: "a", CreatedAt: &created}, wantPublished: true},
+		{name: "archived", record: importRecord{ID: 1, Title: "a", Status: "archived", CreatedAt: &created}, wantPublished: true},
+		{name: "draft", record: importRecord{ID: 1, Title: "a", Status: "draft", CreatedAt: &created}},
+		{name: "markdown file name", record: importRecord{ExternalID: "posts/My Post.md", Title: "a", Slug: "My Post"}, wantSlug: "my-post"},
+		{name: "slug without letters", record: importRecord{ID: 1, Title: "a", Slug: "🙂"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item, err := tt.record.bulkItem()
+			if err != nil {
+				t.Fatalf("bulkItem() error = %v", err)
+			}
+			if item.Slug != tt.wantSlug {
+				t.Errorf("bulkItem() slug = %q, want %q", item.Slug, tt.wantSlug)
+			}
+			if published := item.PublishedAt != nil; published != tt.wantPublished {
+				t.Errorf("bulkItem() published_at set = %v, want %v", published, tt.wantPublished)
+			}
+		})
+	}
+}
+
+func TestParseMarkdown(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		wantTitle   string
+		wantTags    []string
+		wantFormat  string
+		wantContent string
+		wantErr     string
+	}{
+		{
+			name:        "front matter",
+			data:        "---\ntitle: Hello\ntags: [go, web]\n---\n# Hello\n\nBody\n",
+			wantTitle:   "Hello",
+			wantTags:    []string{"go", "web"},
+			wantFormat:  "markdown",
+			wantContent: "# Hello\n\nBody",
+		},
+		{name: "no front matter", data: "# Hello\n\nBody", wantFormat: "markdown", wantContent: "# Hello\n\nBody"},
+		{name: "BOM and CRLF", data: "\ufeff---\r\ntitle: Hello\r\n---\r\nBody\r\n", wantTitle: "Hello", wantFormat: "markdown", wantContent: "Body"},
+		{name: "empty front matter", data: "---\n---\nBody", wantFormat: "markdown", wantContent: "Body"},
+		{name: "closing delimiter at end of file", data: "---\ntitle: Hello\n---", wantTitle: "Hello", wantFormat: "markdown"},
+		{name: "closing delimiter with trailing spaces", data: "---\ntitle: Hello\n---  \nBody", wantTitle: "Hello", wantFormat: "markdown", wantContent: "Body"},
+		{
+			name:        "thematic break in body",
+			data:        "---\ntitle: Hello\n---\nFirst\n\n---\n\nSecond",
+			wantTitle:   "Hello",
+			wantFormat:  "markdown",
+			wantContent: "First\n\n---\n\nSecond",
+		},
+		{name: "longer rule is not a delimiter", data: "---\ntitle: Hello\n----\n---\nBody", wantErr: "invalid front matter"},
+		{name: "content format", data: "---\ncontent_format: html\n---\n<p>Body</p>", wantFormat: "html", wantContent: "<p>Body</p>"},
+		{name: "body starting with a rule", data: "Intro\n---\nMore", wantFormat: "markdown", wantContent: "Intro\n---\nMore"},
+		{name: "unterminated", data: "---\ntitle: Hello\nBody", wantErr: "unterminated front matter"},
+		{name: "invalid yaml", data: "---\ntitle: [unclosed\n---\nBody", wantErr: "invalid front matter"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record, err := parseMarkdown([]byte(tt.data))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parseMarkdown() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseMarkdown() error = %v", err)
+			}
+			if record.Title != tt.wantTitle || record.ContentFormat != tt.wantFormat || record.Content != tt.wantContent {
+				t.Errorf("parseMarkdown() = title %q, format %q, content %q, want %q, %q, %q",
+					record.Title, record.ContentFormat, record.Content, tt.wantTitle, tt.wantFormat, tt.wantContent)
+			}
+			if !reflect.DeepEqual(record.Tags, tt.wantTags) {
+				t.Errorf("parseMarkdown() tags = %v, want %v", record.Tags, tt.wantTags)
+			}
+		})
+	}
+}
+
+func TestParseCSVRecord(t *testing.T) {
+	header := []string{"id", "title", "slug", "tags", "category_id", "status", "published_at", "created_at"}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[name] = i
+	}
+	created := time.Date(2023, 5, 1, 8, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		columns map[string]int
+		values  []string
+		want    *importRecord
+		wantErr string
+	}{
+		{
+			name:    "all columns",
+			columns: columns,
+			values:  []string{"42", " Hello ", "hello", "go, web", "3", "draft", "", "2023-05-01T08:00:00Z"},
+			want:    &importRecord{ID: 42, Title: "Hello", Slug: "hello", Tags: []string{"go, web"}, CategoryID: 3, Status: "draft", CreatedAt: &created},
+		},
+		{
+			name:    "missing columns in header",
+			columns: map[string]int{"title": 0, "content": 1},
+			values:  []string{"Hello", "Body"},
+			want:    &importRecord{Title: "Hello", Content: "Body", Tags: []string{""}},
+		},
+		{
+			name:    "short row",
+			columns: columns,
+			values:  []string{"42", "Hello"},
+			want:    &importRecord{ID: 42, Title: "Hello", Tags: []string{""}},
+		},
+		{name: "invalid id", columns: columns, values: []string{"abc", "Hello"}, wantErr: "invalid id"},
+		{name: "negative category", columns: columns, values: []string{"1", "Hello", "", "", "-1"}, wantErr: "invalid category_id"},
+		{name: "invalid time", columns: columns, values: []string{"1", "Hello", "", "", "", "", "2023-05-01 08:00:00"}, wantErr: "invalid published_at"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record, err := parseCSVRecord(tt.columns, tt.values)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parseCSVRecord() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseCSVRecord() error = %v", err)
+			}
+			if !reflect.DeepEqual(record, tt.want) {
+				t.Errorf("parseCSVRecord() = %+v, want %+v", record, tt.want)
+			}
+		})
+	}
+}
+
+func TestImportAuthorRejectsReservedSubjects(t *testing.T) {
+	service := &ImportService{}
+	for _, subject := range []string{"cli", "apikey:1", "apikey:"} {
+		if _, err := service.importAuthor(map[string]*models.User{}, subject, ""); !errors.Is(err, auth.ErrReservedSubject) {
+			t.Errorf("importAuthor(%q) error = %v, want %v", subject, err, auth.ErrReservedSubject)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"demo/src/common/env"
+	"demo/src/errs"
+	"demo/src/models"
+	"demo/src/repositories"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/go-redis/redis/v8"
+	"gorm.io/gorm"
+	"log"
+	"os"
+	"time"
+)
+
+const (
+	publishSchedulerName  = "publish-scheduler" // 定时发布任务的选主名称
+	publishSchedulerBatch = 100                 // 每次查询的文章数量
+)
+
+// PublishScheduler 定时发布任务，将到达发布时间的定时发布文章改为已发布；
+// 多个实例同时运行时通过Redis选主，只有leader执行发布
+type PublishScheduler struct {
+	mysqlRepo         *repositories.MySQLRepository
+	elasticsearchRepo *repositories.ElasticsearchRepository
+	redisRepo         *repositories.RedisRepository
+	instanceID        string
+	interval          time.Duration
+	leaderTTL         time.Duration
+}
+
+func NewPublishScheduler(db *gorm.DB, esClient *elasticsearch.Client, rdb *redis.Client) *PublishScheduler {
+	interval := env.Duration("SCHEDULER_INTERVAL", 30*time.Second)
+	return &PublishScheduler{
+		mysqlRepo:         repositories.NewMySQLRepository(db),
+		elasticsearchRepo: repositories.NewElasticsearchRepository(esClient),
+		redisRepo:         repositories.NewRedisRepository(rdb),
+		instanceID:        newInstanceID(),
+		interval:          interval,
+		leaderTTL:         3 * interval, // leader连续3轮未续期时由其他实例接管
+	}
+}
+
+// newInstanceID 生成当前实例的唯一标识
+func newInstanceID() string {
+	hostname, _ := os.Hostname()
+	buf := make([]byte, 4)
+	_, _ = rand.Read(buf)
+	return fmt.Sprintf("%s-%d-%s", hostname, os.Getpid(), hex.EncodeToString(buf))
+}
+
+// Run 启动定时发布任务，直到ctx取消
+func (s *PublishScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	defer func() {
+		if err := s.redisRepo.ReleaseLeader(context.Background(), publishSchedulerName, s.instanceID); err != nil {
+			log.Printf("Failed to release scheduler leader: %v", err)
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.tick(ctx)
+		}
+	}
+}
+
+// tick 执行一轮定时发布
+func (s *PublishScheduler) tick(ctx context.Context) {
+	leader, err := s.redisRepo.AcquireLeader(ctx, publishSchedulerName, s.instanceID, s.leaderTTL)
+	if err != nil {
+		log.Printf("Failed to acquire scheduler leader: %v", err)
+		return
+	}
+	if !leader {
+		return
+	}
+
+	// 按(published_at, id)分页处理全部到期的文章，跳过或发布失败的文章不会一直占据第一页
+	now := time.Now()
+	var after *models.Article
+	for ctx.Err() == nil {
+		articles, err := s.mysqlRepo.ListDueScheduledArticles(now, after, publishSchedulerBatch)
+		if err != nil {
+			log.Printf("Failed to list scheduled articles: %v", err)
+			return
+		}
+
+		for i := range articles {
+			if err := s.publish(ctx, &articles[i]); err != nil {
+				log.Printf("Failed to publish scheduled article! ID: %d, Error: %v", articles[i].ID, err)
+			}
+		}
+		if len(articles) < publishSchedulerBatch {
+			return
+		}
+		after = &articles[len(articles)-1]
+	}
+}
+
+// publish 发布一篇定时发布文章：持有文章锁时先更新ES再更新MySQL，任一步失败时MySQL中仍为定时发布状态，
+// 下一轮会重试；文章锁被占用（正在被修改）时跳过，下一轮再处理
+func (s *PublishScheduler) publish(ctx context.Context, article *models.Article) error {
+	locked, err := s.redisRepo.LockArticleID(ctx, article.ID)
+	if err != nil || !locked {
+		return err
+	}
+	defer func() {
+		if err := s.redisRepo.UnlockArticleID(ctx, article.ID); err != nil {
+			log.Printf("Failed to unlock article! ID: %d, Error: %v", article.ID, err)
+		}
+	}()
+
+	// 加锁前文章可能已被修改或删除
+	current, err := s.mysqlRepo.FindArticle(article.ID)
+	if errors.Is(err, errs.ErrArticleNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if current.Status != models.ArticleStatusScheduled || current.PublishedAt == nil || current.PublishedAt.After(time.Now()) {
+		return nil
+	}
+
+	if err := s.elasticsearchRepo.UpdateArticleStatus(ctx, current.ID, models.ArticleStatusPublished, current.PublishedAt); err != nil {
+		return fmt.Errorf("updating ES: %w", err)
+	}
+	if _, err := s.mysqlRepo.PublishScheduledArticle(current.ID); err != nil {
+		return fmt.Errorf("updating MySQL: %w", err)
+	}
+	log.Printf("Scheduled article published. ID: %d", current.ID)
+	return nil
+}
@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"demo/src/common/auth"
+	"demo/src/dtos"
+	"demo/src/errs"
+	"demo/src/models"
+	"demo/src/repositories"
+	"github.com/elastic/go-elasticsearch/v8"
+	"gorm.io/gorm"
+	"strings"
+	"time"
+)
+
+// searchTermsLock 多个实例修改同义词和停用词时串行写入同义词文件的MySQL锁名
+const searchTermsLock = "demo:search_terms"
+
+// searchTermsLockTimeout 等待其他实例写入同义词文件的最长时间
+const searchTermsLockTimeout = 30 * time.Second
+
+// SearchTermService 管理检索使用的同义词和停用词，修改后写入同义词文件并重新加载文章索引的检索分析器
+type SearchTermService struct {
+	mysqlRepo         *repositories.MySQLRepository
+	elasticsearchRepo *repositories.ElasticsearchRepository
+	policy            *auth.Policy
+}
+
+func NewSearchTermService(db *gorm.DB, esClient *elasticsearch.Client, policy *auth.Policy) *SearchTermService {
+	return &SearchTermService{
+		mysqlRepo:         repositories.NewMySQLRepository(db),
+		elasticsearchRepo: repositories.NewElasticsearchRepository(esClient),
+		policy:            policy,
+	}
+}
+
+// loadTerms 从数据库读取全部同义词和停用词
+func (s *SearchTermService) loadTerms() (repositories.SearchTerms, error) {
+	var terms repositories.SearchTerms
+
+	synonyms, err := s.mysqlRepo.ListSearchSynonyms()
+	if err != nil {
+		return terms, err
+	}
+	for _, synonym := range synonyms {
+		terms.Synonyms = append(terms.Synonyms, synonym.Terms)
+	}
+
+	stopwords, err := s.mysqlRepo.ListSearchStopwords()
+	if err != nil {
+		return terms, err
+	}
+	for _, stopword := range stopwords {
+		terms.Stopwords = append(terms.Stopwords, stopword.Word)
+	}
+
+	return terms, nil
+}
+
+// WriteTermsFile 将数据库中的同义词和停用词写入同义词文件，创建文章索引前调用
+func (s *SearchTermService) WriteTermsFile(ctx context.Context) error {
+	return s.mysqlRepo.WithLock(searchTermsLock, searchTermsLockTimeout, func() error {
+		terms, err := s.loadTerms()
+		if err != nil {
+			return err
+		}
+		return repositories.WriteSynonymsFile(terms)
+	})
+}
+
+// applyTerms 将数据库中的同义词和停用词写入同义词文件，并重新加载文章索引的检索分析器，索引不需要关闭。
+// 加锁后从数据库读取最新的全部配置，多个实例并发修改时后写入的一定包含先提交的修改
+func (s *SearchTermService) applyTerms(ctx context.Context) error {
+	return s.mysqlRepo.WithLock(searchTermsLock, searchTermsLockTimeout, func() error {
+		terms, err := s.loadTerms()
+		if err != nil {
+			return err
+		}
+		if err := repositories.WriteSynonymsFile(terms); err != nil {
+			return err
+		}
+		return s.elasticsearchRepo.ReloadSearchAnalyzers(ctx)
+	})
+}
+
+// normalizeSynonymTerms 去除首尾空格和重复项，同义词不能包含同义词规则的分隔符
+func normalizeSynonymTerms(terms []string) (models.StringList, error) {
+	normalized := models.StringList{}
+	seen := make(map[string]bool)
+	for _, term := range terms {
+		term = strings.TrimSpace(term)
+		if strings.Contains(term, ",") || strings.Contains(term, "=>") {
+			return nil, errs.ErrInvalidSynonym
+		}
+		if term == "" || seen[term] {
+			continue
+		}
+		seen[term] = true
+		normalized = append(normalized, term)
+	}
+	if len(normalized) < 2 {
+		return nil, errs.ErrInvalidSynonym
+	}
+	return normalized, nil
+}
+
+// ListSynonyms 获取全部同义词组
+func (s *SearchTermService) ListSynonyms(ctx context.Context) ([]models.SearchSynonym, error) {
+	return s.mysqlRepo.ListSearchSynonyms()
+}
+
+// AddSynonym 新增同义词组
+func (s *SearchTermService) AddSynonym(ctx context.Context, req *dtos.SearchSynonymRequest) (uint64, error) {
+	if err := authorize(ctx, s.policy, auth.PermManageTaxonomy); err != nil {
+		return 0, err
+	}
+
+	terms, err := normalizeSynonymTerms(req.Terms)
+	if err != nil {
+		return 0, err
+	}
+
+	synonym := models.SearchSynonym{Terms: terms}
+	if err := s.mysqlRepo.AddSearchSynonym(&synonym); err != nil {
+		return 0, err
+	}
+	return synonym.ID, s.applyTerms(ctx)
+}
+
+// UpdateSynonym 更新同义词组
+func (s *SearchTermService) UpdateSynonym(ctx context.Context, synonymID uint64, req *dtos.SearchSynonymRequest) error {
+	if err := authorize(ctx, s.policy, auth.PermManageTaxonomy); err != nil {
+		return err
+	}
+
+	terms, err := normalizeSynonymTerms(req.Terms)
+	if err != nil {
+		return err
+	}
+
+	synonym := models.SearchSynonym{ID: synonymID, Terms: terms, UpdatedAt: time.Now()}
+	if err := s.mysqlRepo.UpdateSearchSynonym(&synonym); err != nil {
+		return err
+	}
+	return s.applyTerms(ctx)
+}
+
+// DeleteSynonym 删除同义词组
+func (s *SearchTermService) DeleteSynonym(ctx context.Context, synonymID uint64) error {
+	if err := authorize(ctx, s.policy, auth.PermManageTaxonomy); err != nil {
+		return err
+	}
+
+	if err := s.mysqlRepo.DeleteSearchSynonym(synonymID); err != nil {
+		return err
+	}
+	return s.applyTerms(ctx)
+}
+
+// ListStopwords 获取全部停用词
+func (s *SearchTermService) ListStopwords(ctx context.Context) ([]models.SearchStopword, error) {
+	return s.mysqlRepo.ListSearchStopwords()
+}
+
+// AddStopword 新增停用词，停用词在小写转换之后匹配，统一存储为小写
+func (s *SearchTermService) AddStopword(ctx context.Context, req *dtos.SearchStopwordRequest) (uint64, error) {
+	if err := authorize(ctx, s.policy, auth.PermManageTaxonomy); err != nil {
+		return 0, err
+	}
+
+	word := strings.ToLower(strings.TrimSpace(req.Word))
+	if strings.Contains(word, ",") || strings.Contains(word, "=>") {
+		return 0, errs.ErrInvalidStopword
+	}
+
+	stopword := models.SearchStopword{Word: word}
+	if err := s.mysqlRepo.AddSearchStopword(&stopword); err != nil {
+		return 0, err
+	}
+	return stopword.ID, s.applyTerms(ctx)
+}
+
+// DeleteStopword 删除停用词
+func (s *SearchTermService) DeleteStopword(ctx context.Context, stopwordID uint64) error {
+	if err := authorize(ctx, s.policy, auth.PermManageTaxonomy); err != nil {
+		return err
+	}
+
+	if err := s.mysqlRepo.DeleteSearchStopword(stopwordID); err != nil {
+		return err
+	}
+	return s.applyTerms(ctx)
+}
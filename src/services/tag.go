@@ -0,0 +1,28 @@
+package services
+
+import (
+	"context"
+	"demo/src/dtos"
+	"demo/src/repositories"
+	"github.com/elastic/go-elasticsearch/v8"
+)
+
+const defaultTagListSize = 100 // 默认返回的标签数量
+
+type TagService struct {
+	elasticsearchRepo *repositories.ElasticsearchRepository
+}
+
+func NewTagService(esClient *elasticsearch.Client) *TagService {
+	return &TagService{
+		elasticsearchRepo: repositories.NewElasticsearchRepository(esClient),
+	}
+}
+
+// ListTags 获取标签及使用次数
+func (s *TagService) ListTags(ctx context.Context, size int) ([]dtos.TagCount, error) {
+	if size == 0 {
+		size = defaultTagListSize
+	}
+	return s.elasticsearchRepo.CountTags(ctx, size)
+}
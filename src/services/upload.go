@@ -0,0 +1,216 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"demo/src/common/auth"
+	"demo/src/common/env"
+	"demo/src/common/imaging"
+	"demo/src/dtos"
+	"demo/src/errs"
+	"demo/src/models"
+	"demo/src/repositories"
+	"encoding/hex"
+	"fmt"
+	"github.com/gabriel-vasile/mimetype"
+	_ "golang.org/x/image/webp" // 注册webp解码器
+	"image"
+	_ "image/gif"  // 注册gif解码器
+	_ "image/jpeg" // 注册jpeg解码器
+	_ "image/png"  // 注册png解码器
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+// allowedImageTypes 允许上传的图片类型及对应的文件扩展名
+var allowedImageTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// 图片缩略图边长及响应式宽度版本
+const thumbnailSize = 200
+
+var variantWidths = []int{320, 640, 1024}
+
+type UploadService struct {
+	storage   repositories.FileStorage
+	policy    *auth.Policy
+	maxSize   int64 // 文件大小上限（字节）
+	maxWidth  int   // 图片宽度上限（像素）
+	maxHeight int   // 图片高度上限（像素）
+}
+
+func NewUploadService(storage repositories.FileStorage, policy *auth.Policy) *UploadService {
+	return &UploadService{
+		storage:   storage,
+		policy:    policy,
+		maxSize:   env.Int64("UPLOAD_MAX_SIZE", 5<<20),
+		maxWidth:  env.Int("UPLOAD_MAX_WIDTH", 8000),
+		maxHeight: env.Int("UPLOAD_MAX_HEIGHT", 8000),
+	}
+}
+
+// MaxSize 上传文件大小上限
+func (s *UploadService) MaxSize() int64 {
+	return s.maxSize
+}
+
+// UploadImage 校验并保存上传的图片，返回图片访问地址；上传的图片用于文章，需要create权限
+func (s *UploadService) UploadImage(ctx context.Context, r io.Reader) (*dtos.UploadResultData, error) {
+	if err := authorize(ctx, s.policy, auth.PermCreate); err != nil {
+		return nil, err
+	}
+
+	// 多读取一个字节用于判断是否超过大小限制
+	data, err := io.ReadAll(io.LimitReader(r, s.maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > s.maxSize {
+		return nil, fmt.Errorf("%w: limit is %d bytes", errs.ErrFileTooLarge, s.maxSize)
+	}
+
+	// 根据文件内容嗅探类型，不信任客户端提供的Content-Type和文件名
+	mimeType := mimetype.Detect(data).String()
+	ext, ok := allowedImageTypes[mimeType]
+	if !ok {
+		return nil, errs.ErrUnsupportedFileType
+	}
+
+	// 先只解析图片头部获取尺寸，避免解码超大图片
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, errs.ErrUnsupportedFileType
+	}
+	if cfg.Width > s.maxWidth || cfg.Height > s.maxHeight {
+		return nil, fmt.Errorf("%w: limit is %dx%d", errs.ErrImageTooLarge, s.maxWidth, s.maxHeight)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, errs.ErrUnsupportedFileType
+	}
+
+	key, err := generateImageKey(ext)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.storage.Save(ctx, key, bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+
+	// 生成缩略图和不同宽度的图片版本
+	variants, err := s.saveVariants(ctx, key, img)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dtos.UploadResultData{
+		URL:      s.storage.URL(key),
+		MimeType: mimeType,
+		Size:     int64(len(data)),
+		Width:    cfg.Width,
+		Height:   cfg.Height,
+		Variants: variants,
+	}, nil
+}
+
+// saveVariants 生成并保存图片的缩略图和宽度版本，文件与原图存放在同一目录
+func (s *UploadService) saveVariants(ctx context.Context, key string, img image.Image) (models.PictureVariants, error) {
+	variants := models.PictureVariants{}
+
+	save := func(name string, variant image.Image) error {
+		variantKey := variantImageKey(key, name)
+		var buf bytes.Buffer
+		if err := imaging.Encode(&buf, variant, path.Ext(variantKey)); err != nil {
+			return err
+		}
+		if err := s.storage.Save(ctx, variantKey, &buf); err != nil {
+			return err
+		}
+		variants[name] = s.storage.URL(variantKey)
+		return nil
+	}
+
+	if err := save("thumb", imaging.Thumbnail(img, thumbnailSize)); err != nil {
+		return nil, err
+	}
+
+	for _, width := range variantWidths {
+		// 不放大比原图更窄的图片
+		if width >= img.Bounds().Dx() {
+			break
+		}
+		if err := save(fmt.Sprintf("%dw", width), imaging.Resize(img, width)); err != nil {
+			return nil, err
+		}
+	}
+
+	return variants, nil
+}
+
+// PictureVariants 查找图片地址对应的已生成版本，非本服务上传的图片返回nil；
+// 地址指向本服务的存储但原图不存在时返回ErrPictureNotFound，避免保存没有缩略图的文章
+// （本地存储未在各实例间共享时，在其他实例上传的图片同样找不到）
+func (s *UploadService) PictureVariants(ctx context.Context, picture string) (models.PictureVariants, error) {
+	key, ok := s.storage.Key(picture)
+	if !ok {
+		return nil, nil
+	}
+	exists, err := s.storage.Exists(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errs.ErrPictureNotFound
+	}
+
+	variants := models.PictureVariants{}
+	names := []string{"thumb"}
+	for _, width := range variantWidths {
+		names = append(names, fmt.Sprintf("%dw", width))
+	}
+
+	for _, name := range names {
+		variantKey := variantImageKey(key, name)
+		exists, err := s.storage.Exists(ctx, variantKey)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			variants[name] = s.storage.URL(variantKey)
+		}
+	}
+
+	if len(variants) == 0 {
+		return nil, nil
+	}
+	return variants, nil
+}
+
+// variantImageKey 生成图片版本的存储key，如 a1b2.jpg 的320w版本为 a1b2_320w.jpg；
+// png和gif版本编码为png以保留透明通道，其余编码为jpeg
+func variantImageKey(key, name string) string {
+	ext := path.Ext(key)
+	variantExt := ".jpg"
+	if ext == ".png" || ext == ".gif" {
+		variantExt = ".png"
+	}
+	return strings.TrimSuffix(key, ext) + "_" + name + variantExt
+}
+
+// generateImageKey 生成图片存储key，格式为 images/年/月/随机名.扩展名
+func generateImageKey(ext string) (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("images/%s/%s%s", time.Now().Format("2006/01"), hex.EncodeToString(buf), ext), nil
+}
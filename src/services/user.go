@@ -0,0 +1,50 @@
+package services
+
+import (
+	"context"
+	"demo/src/common/auth"
+	"demo/src/errs"
+	"demo/src/models"
+	"demo/src/repositories"
+	"github.com/elastic/go-elasticsearch/v8"
+	"gorm.io/gorm"
+	"log"
+)
+
+type UserService struct {
+	mysqlRepo         *repositories.MySQLRepository
+	elasticsearchRepo *repositories.ElasticsearchRepository
+}
+
+func NewUserService(db *gorm.DB, esClient *elasticsearch.Client) *UserService {
+	return &UserService{
+		mysqlRepo:         repositories.NewMySQLRepository(db),
+		elasticsearchRepo: repositories.NewElasticsearchRepository(esClient),
+	}
+}
+
+// CurrentUser 获取当前请求的用户，首次访问时创建，JWT中的名称变化时同步更新ES中文章的作者名称
+func (s *UserService) CurrentUser(ctx context.Context) (*models.User, error) {
+	principal := auth.FromContext(ctx)
+	if principal == nil {
+		return nil, errs.ErrUnauthenticated
+	}
+
+	name := principal.Name
+	if name == "" {
+		name = principal.Subject
+	}
+
+	user, nameChanged, err := s.mysqlRepo.UpsertUser(principal.Subject, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if nameChanged {
+		if err := s.elasticsearchRepo.UpdateAuthorName(ctx, user.ID, user.Name); err != nil {
+			log.Printf("Failed to update author name in ES! UserID: %d, Error: %v", user.ID, err)
+		}
+	}
+
+	return user, nil
+}